@@ -3,39 +3,119 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// Base URL for all Clockify API requests
-const baseURL = "https://api.clockify.me/api/v1"
+// DefaultBaseURL is where Clockify's API lives unless a profile points elsewhere
+// (e.g., a regional or self-hosted instance)
+const DefaultBaseURL = "https://api.clockify.me/api/v1"
+
+// Defaults used when NewClient isn't given options
+const (
+	DefaultTimeout    = 30 * time.Second // Per attempt, including reading the body
+	DefaultMaxRetries = 3                // Extra attempts after the first one
+)
 
 // Client handles all HTTP interactions with the Clockify API
 // It stores the API key and reuses an HTTP client for efficiency
+// A single Client is safe to share between goroutines
 type Client struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
+	maxRetries int
+	baseDelay  time.Duration // First backoff delay; doubles on every retry
+	maxDelay   time.Duration // Upper bound for a single backoff delay
+}
+
+// Option customizes a Client - pass any number of them to NewClient
+type Option func(*Client)
+
+// WithTimeout sets how long a single attempt may take before it's abandoned
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithMaxRetries sets how many times a failed request may be retried
+// Zero disables retries entirely
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// WithBaseURL points the client at a different Clockify API
+// An empty URL keeps the default
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
 }
 
 // NewClient creates and returns a new Clockify API client
 // This is the constructor function - always use this to create clients
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
-		httpClient: &http.Client{}, // Standard HTTP client
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  500 * time.Millisecond,
+		maxDelay:   30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // doRequest is a private helper method that performs HTTP requests
+// It retries failures that are safe to retry (see shouldRetry), waiting
+// longer between each attempt, and returns the last error if all attempts fail
+// Cancelling ctx aborts the request in flight and any wait between retries
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		respBody, err := c.doOnce(ctx, method, endpoint, body)
+		if err == nil {
+			return respBody, nil
+		}
+
+		if ctx.Err() != nil || attempt >= c.maxRetries || !shouldRetry(method, err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &APIError{Kind: ErrNetwork, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// doOnce performs a single HTTP request
 // It handles:
 // - JSON marshaling of request bodies
 // - Adding authentication headers
-// - Error handling for non-2xx responses
-func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
+// - Error handling for non-2xx responses (see errors.go for how failures are classified)
+func (c *Client) doOnce(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
 	var reqBody io.Reader
-	
+
 	// If we have a body, marshal it to JSON
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -46,7 +126,7 @@ func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, e
 	}
 
 	// Create the HTTP request
-	req, err := http.NewRequest(method, baseURL+endpoint, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -60,30 +140,112 @@ func (c *Client) doRequest(method, endpoint string, body interface{}) ([]byte, e
 	// Execute the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &APIError{Kind: ErrNetwork, Err: err}
 	}
 	defer resp.Body.Close() // Always close the response body
 
 	// Read the response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, &APIError{Kind: ErrNetwork, Err: fmt.Errorf("failed to read response: %w", err)}
 	}
 
 	// Check for HTTP errors
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		apiErr := newStatusError(resp.StatusCode, respBody)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, apiErr
 	}
 
 	return respBody, nil
 }
 
+// shouldRetry decides whether a failed request can be sent again
+//
+// 429 and 503 mean Clockify didn't process the request, and a refused
+// connection means it never arrived, so those are safe for every method.
+// Other network and server errors are ambiguous - the request may have
+// succeeded before the failure - so they're only retried for idempotent
+// methods. POST and PATCH are never repeated in that case, otherwise a
+// lost response could create the same time entry twice.
+func shouldRetry(method string, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false // e.g., marshaling failed - retrying won't help
+	}
+
+	switch {
+	case apiErr.Kind == ErrRateLimit, apiErr.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case apiErr.Kind == ErrNetwork && isConnectError(apiErr.Err):
+		return true
+	case apiErr.Kind == ErrNetwork, apiErr.Kind == ErrServer:
+		return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	}
+	return false
+}
+
+// isConnectError reports whether the request failed before anything was sent
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoff returns how long to wait before retry number attempt (starting at 0)
+// Retry-After from the server wins; otherwise it's exponential with jitter
+// so many clients hitting the rate limit at once don't retry in lockstep
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, c.maxDelay)
+	}
+
+	delay := min(c.baseDelay<<attempt, c.maxDelay)
+
+	// "Equal jitter": wait somewhere between half and all of the delay
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date; returns 0 if it's missing or unreadable
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil && when.After(now) {
+		return when.Sub(now)
+	}
+	return 0
+}
+
 // get performs a GET request - convenience wrapper around doRequest
-func (c *Client) get(endpoint string) ([]byte, error) {
-	return c.doRequest("GET", endpoint, nil)
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	return c.doRequest(ctx, "GET", endpoint, nil)
 }
 
 // post performs a POST request - convenience wrapper around doRequest
-func (c *Client) post(endpoint string, body interface{}) ([]byte, error) {
-	return c.doRequest("POST", endpoint, body)
+func (c *Client) post(ctx context.Context, endpoint string, body interface{}) ([]byte, error) {
+	return c.doRequest(ctx, "POST", endpoint, body)
+}
+
+// put performs a PUT request - convenience wrapper around doRequest
+func (c *Client) put(ctx context.Context, endpoint string, body interface{}) ([]byte, error) {
+	return c.doRequest(ctx, "PUT", endpoint, body)
+}
+
+// patch performs a PATCH request - convenience wrapper around doRequest
+func (c *Client) patch(ctx context.Context, endpoint string, body interface{}) ([]byte, error) {
+	return c.doRequest(ctx, "PATCH", endpoint, body)
+}
+
+// delete performs a DELETE request - convenience wrapper around doRequest
+func (c *Client) delete(ctx context.Context, endpoint string) ([]byte, error) {
+	return c.doRequest(ctx, "DELETE", endpoint, nil)
 }
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient is a client for a test server that counts the requests it gets
+// Backoff delays are cut to a millisecond so retries don't slow the tests down
+func testClient(t *testing.T, handler http.HandlerFunc, maxRetries int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithMaxRetries(maxRetries))
+	client.baseDelay = time.Millisecond
+	client.maxDelay = time.Millisecond
+	return client, &attempts
+}
+
+// status is a handler that always answers with code
+func status(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		method   string
+		status   int
+		attempts int32
+	}{
+		{http.MethodGet, http.StatusBadGateway, 3},
+		{http.MethodGet, http.StatusInternalServerError, 3},
+		{http.MethodPut, http.StatusBadGateway, 3},
+		{http.MethodDelete, http.StatusBadGateway, 3},
+		{http.MethodGet, http.StatusNotFound, 1},     // Asking again won't find it
+		{http.MethodGet, http.StatusUnauthorized, 1}, // Nor fix the key
+		// The entry may have been created before the failure
+		{http.MethodPost, http.StatusBadGateway, 1},
+		{http.MethodPatch, http.StatusInternalServerError, 1},
+		// Clockify didn't process these, so even a POST is safe to send again
+		{http.MethodPost, http.StatusTooManyRequests, 3},
+		{http.MethodPost, http.StatusServiceUnavailable, 3},
+	}
+	for _, tt := range tests {
+		client, attempts := testClient(t, status(tt.status), 2)
+		if _, err := client.doRequest(context.Background(), tt.method, "/test", nil); err == nil {
+			t.Errorf("%s answered with %d succeeded", tt.method, tt.status)
+		}
+		if got := attempts.Load(); got != tt.attempts {
+			t.Errorf("%s answered with %d was sent %d times, want %d", tt.method, tt.status, got, tt.attempts)
+		}
+	}
+}
+
+func TestRetriesSucceed(t *testing.T) {
+	// Clockify recovers after two failures
+	var calls atomic.Int32
+	client, attempts := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id":"user-1"}`))
+	}, 3)
+
+	body, err := client.doRequest(context.Background(), http.MethodGet, "/user", nil)
+	if err != nil || string(body) != `{"id":"user-1"}` {
+		t.Errorf("doRequest = %q, %v; want the third answer", body, err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("sent %d times, want 3", got)
+	}
+}
+
+func TestNoRetries(t *testing.T) {
+	client, attempts := testClient(t, status(http.StatusBadGateway), 0)
+	if _, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil); err == nil {
+		t.Error("a 502 succeeded")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("sent %d times with retries turned off, want 1", got)
+	}
+}
+
+func TestResetConnection(t *testing.T) {
+	// The connection drops after the request arrived, so it may have been processed
+	client, attempts := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	}, 2)
+
+	_, err := client.doRequest(context.Background(), http.MethodPost, "/entries", map[string]string{"description": "x"})
+	if KindOf(err) != ErrNetwork {
+		t.Errorf("error = %v, want a network error", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("POST was sent %d times after the connection was reset, want 1", got)
+	}
+}
+
+func TestRefusedConnection(t *testing.T) {
+	// Nothing listens, so nothing was sent - even a POST may be tried again
+	server := httptest.NewServer(status(http.StatusOK))
+	server.Close()
+	_, err := NewClient("test-key", WithBaseURL(server.URL), WithMaxRetries(0)).doRequest(context.Background(), http.MethodPost, "/entries", nil)
+	if !shouldRetry(http.MethodPost, err) {
+		t.Errorf("a refused POST (%v) isn't retried", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"0", 0},
+		{"-5", 0},
+		{"soon", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0}, // Already past
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffHonoursRetryAfter(t *testing.T) {
+	// Both forms of the header reach the error, for 429 and 503 alike
+	tests := []struct {
+		status int
+		header string
+		want   time.Duration
+	}{
+		{http.StatusTooManyRequests, "7", 7 * time.Second},
+		{http.StatusServiceUnavailable, "7", 7 * time.Second},
+		{http.StatusTooManyRequests, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), time.Hour},
+		{http.StatusServiceUnavailable, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), time.Hour},
+	}
+	for _, tt := range tests {
+		client, _ := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", tt.header)
+			w.WriteHeader(tt.status)
+		}, 0)
+		client.maxDelay = 2 * time.Hour
+
+		_, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil)
+		// An HTTP date only has whole seconds, and a moment passes before it's read
+		if got := client.backoff(0, err); got > tt.want || got < tt.want-2*time.Second {
+			t.Errorf("%d with Retry-After %q waits %v, want %v", tt.status, tt.header, got, tt.want)
+		}
+	}
+
+	// However long the server asks for, one wait is capped
+	client := NewClient("test-key")
+	if got := client.backoff(0, &APIError{Kind: ErrRateLimit, RetryAfter: time.Hour}); got != client.maxDelay {
+		t.Errorf("Retry-After of an hour waits %v, want the %v cap", got, client.maxDelay)
+	}
+}
+
+func TestBackoffGrows(t *testing.T) {
+	client := NewClient("test-key")
+	err := &APIError{Kind: ErrServer, StatusCode: http.StatusBadGateway}
+	for attempt := range 8 {
+		// Jitter keeps each wait between half and all of the doubled delay
+		delay := min(client.baseDelay<<attempt, client.maxDelay)
+		if got := client.backoff(attempt, err); got < delay/2 || got > delay {
+			t.Errorf("retry %d waits %v, want between %v and %v", attempt, got, delay/2, delay)
+		}
+	}
+}
+
+func TestCancelDuringBackoff(t *testing.T) {
+	// Clockify asks for a long wait, and the user gives up during it
+	client, attempts := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, 3)
+	client.maxDelay = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.doRequest(ctx, http.MethodGet, "/test", nil)
+	if !IsCanceled(err) {
+		t.Errorf("error = %v, want a cancelled request", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelling took %v, want it to stop waiting right away", elapsed)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("sent %d times, want no retry after cancelling", got)
+	}
+}
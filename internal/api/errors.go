@@ -0,0 +1,132 @@
+// Classifies API failures so callers can decide whether to retry or give up
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorKind groups API failures by how the caller should react to them
+type ErrorKind int
+
+const (
+	ErrUnknown    ErrorKind = iota // Not an API error (e.g., bad input caught before sending)
+	ErrNetwork                     // The request never got a response - offline, DNS, timeout
+	ErrAuth                        // 401/403 - bad API key or no access to the resource
+	ErrValidation                  // 400/404/422 - Clockify rejected what we sent
+	ErrRateLimit                   // 429 - too many requests, slow down
+	ErrServer                      // 5xx - Clockify had a problem, usually temporary
+)
+
+// String returns a short human-readable name for the kind
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrNetwork:
+		return "network error"
+	case ErrAuth:
+		return "authentication failed"
+	case ErrValidation:
+		return "invalid request"
+	case ErrRateLimit:
+		return "rate limited"
+	case ErrServer:
+		return "server error"
+	}
+	return "error"
+}
+
+// APIError is returned by every Client method when a request fails
+// Use errors.As or KindOf to inspect it through wrapped errors
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int           // 0 for network errors
+	Message    string        // Clockify's message, or the raw response body
+	Err        error         // Underlying error for network failures
+	RetryAfter time.Duration // How long the server asked us to wait (429/503), if it said
+}
+
+// Error formats the error like "server error (status 502): Bad Gateway"
+func (e *APIError) Error() string {
+	if e.Kind == ErrNetwork {
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("%s (status %d)", e.Kind, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (status %d): %s", e.Kind, e.StatusCode, e.Message)
+}
+
+// Unwrap lets errors.Is/As see the underlying network error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of an error returned by the client
+// Errors that didn't come from an API call are ErrUnknown
+func KindOf(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+	return ErrUnknown
+}
+
+// IsCanceled reports whether err happened because the caller cancelled the request
+// These aren't real failures and usually shouldn't be shown to the user
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// IsAuth reports whether err means the API key was rejected
+func IsAuth(err error) bool {
+	return KindOf(err) == ErrAuth
+}
+
+// IsTemporary reports whether trying the same request again later might succeed
+func IsTemporary(err error) bool {
+	switch KindOf(err) {
+	case ErrNetwork, ErrRateLimit, ErrServer:
+		return true
+	}
+	return false
+}
+
+// newStatusError builds an APIError from a non-2xx response
+func newStatusError(statusCode int, body []byte) *APIError {
+	return &APIError{
+		Kind:       kindForStatus(statusCode),
+		StatusCode: statusCode,
+		Message:    errorMessage(body),
+	}
+}
+
+// kindForStatus maps an HTTP status code to an ErrorKind
+func kindForStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimit
+	case statusCode >= 500:
+		return ErrServer
+	case statusCode >= 400:
+		return ErrValidation
+	}
+	return ErrUnknown
+}
+
+// errorMessage pulls the "message" field out of a Clockify error body
+// Falls back to the raw body when it isn't JSON
+func errorMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return string(body)
+}
@@ -0,0 +1,64 @@
+// Walks Clockify's paginated list endpoints
+// Clockify returns one page (50 items by default) per request, so anything
+// that should see a whole list has to keep asking for the next page
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageSize is how many items we ask for per request
+// Bigger pages mean fewer round trips; Clockify allows up to 5000
+const PageSize = 200
+
+// forEachPage requests endpoint page by page, decoding each page into []T
+// and handing it to fn. It stops when a page comes back short (the last one)
+// or when fn returns false. Any query parameters in query are kept on every request.
+func forEachPage[T any](ctx context.Context, c *Client, endpoint string, query url.Values, fn func(page []T) bool) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page-size", strconv.Itoa(PageSize))
+
+	// Endpoints may already include a query string
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		body, err := c.get(ctx, endpoint+separator+query.Encode())
+		if err != nil {
+			return err
+		}
+
+		var items []T
+		if err := json.Unmarshal(body, &items); err != nil {
+			return fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+
+		if !fn(items) || len(items) < PageSize {
+			return nil
+		}
+	}
+}
+
+// getAllPages collects every page of endpoint into a single slice
+func getAllPages[T any](ctx context.Context, c *Client, endpoint string, query url.Values) ([]T, error) {
+	var all []T
+	err := forEachPage(ctx, c, endpoint, query, func(page []T) bool {
+		all = append(all, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -1,29 +1,113 @@
-// Functions for fetching projects from Clockify
+// Functions for fetching projects, clients and tags from Clockify
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 )
 
 // GetProjects fetches all projects for a given workspace
 // Returns a slice of Project structs or an error
-func (c *Client) GetProjects(workspaceID string) ([]Project, error) {
+func (c *Client) GetProjects(ctx context.Context, workspaceID string) ([]Project, error) {
 	// Build the endpoint URL with the workspace ID
 	endpoint := fmt.Sprintf("/workspaces/%s/projects", workspaceID)
-	
-	// Make the GET request
-	body, err := c.get(endpoint)
+
+	// Walk every page - large workspaces have hundreds of projects
+	projects, err := getAllPages[Project](ctx, c, endpoint, nil)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
 	}
 
-	// Parse the JSON response into a slice of Project structs
-	var projects []Project
-	if err := json.Unmarshal(body, &projects); err != nil {
-		return nil, fmt.Errorf("failed to parse projects: %w", err)
+	c.fillClientNames(ctx, workspaceID, projects)
+	return projects, nil
+}
+
+// fillClientNames looks up client names Clockify left out of the project list
+// It usually includes them, so the clients are only fetched when one is missing.
+// The names are only shown and searched, so if that fetch fails they stay blank
+func (c *Client) fillClientNames(ctx context.Context, workspaceID string, projects []Project) {
+	missing := false
+	for _, project := range projects {
+		if project.ClientID != "" && project.ClientName == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return
 	}
 
-	return projects, nil
+	customers, err := c.GetCustomers(ctx, workspaceID)
+	if err != nil {
+		return
+	}
+	names := make(map[string]string, len(customers))
+	for _, customer := range customers {
+		names[customer.ID] = customer.Name
+	}
+	for i, project := range projects {
+		if project.ClientName == "" {
+			projects[i].ClientName = names[project.ClientID]
+		}
+	}
+}
+
+// GetProjectTasks fetches the active tasks of a single project
+// Most projects have none, in which case the slice is empty
+func (c *Client) GetProjectTasks(ctx context.Context, workspaceID, projectID string) ([]ProjectTask, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", workspaceID, projectID)
+
+	// Done tasks can't have time logged against them
+	query := url.Values{}
+	query.Set("is-active", "true")
+
+	tasks, err := getAllPages[ProjectTask](ctx, c, endpoint, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch project tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// GetCustomers fetches all clients (customers) for a given workspace
+func (c *Client) GetCustomers(ctx context.Context, workspaceID string) ([]Customer, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/clients", workspaceID)
+
+	customers, err := getAllPages[Customer](ctx, c, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch clients: %w", err)
+	}
+
+	return customers, nil
+}
+
+// CreateTag adds a new tag to the workspace and returns it
+func (c *Client) CreateTag(ctx context.Context, workspaceID, name string) (*Tag, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/tags", workspaceID)
+
+	body, err := c.post(ctx, endpoint, TagRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	var tag Tag
+	if err := json.Unmarshal(body, &tag); err != nil {
+		return nil, fmt.Errorf("failed to parse tag: %w", err)
+	}
+
+	return &tag, nil
 }
 
+// GetTags fetches all tags for a given workspace
+func (c *Client) GetTags(ctx context.Context, workspaceID string) ([]Tag, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/tags", workspaceID)
+
+	tags, err := getAllPages[Tag](ctx, c, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tags: %w", err)
+	}
+
+	return tags, nil
+}
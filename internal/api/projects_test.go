@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetProjectsFillsClientNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		projects    string
+		clients     int    // Status of the clients endpoint
+		want        string // Client name of each project, comma separated
+		wantClients bool   // Whether the clients had to be fetched
+	}{
+		{
+			"all named",
+			`[{"id":"p1","clientId":"c1","clientName":"Acme"},{"id":"p2"}]`,
+			http.StatusOK, "Acme,", false,
+		},
+		{
+			"one missing",
+			`[{"id":"p1","clientId":"c1","clientName":"Acme"},{"id":"p2","clientId":"c2"},{"id":"p3"}]`,
+			http.StatusOK, "Acme,Globex,", true,
+		},
+		{
+			"clients unavailable",
+			`[{"id":"p1","clientId":"c2"}]`,
+			http.StatusForbidden, "", true,
+		},
+	}
+	for _, tt := range tests {
+		fetchedClients := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/workspaces/ws-1/projects":
+				fmt.Fprint(w, tt.projects)
+			case "/workspaces/ws-1/clients":
+				fetchedClients = true
+				w.WriteHeader(tt.clients)
+				fmt.Fprint(w, `[{"id":"c1","name":"Acme"},{"id":"c2","name":"Globex"}]`)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		projects, err := NewClient("test-key", WithBaseURL(server.URL), WithMaxRetries(0)).GetProjects(context.Background(), "ws-1")
+		server.Close()
+		if err != nil {
+			t.Errorf("%s: GetProjects failed: %v", tt.name, err)
+			continue
+		}
+		var names []string
+		for _, project := range projects {
+			names = append(names, project.ClientName)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("%s: client names = %q, want %q", tt.name, got, tt.want)
+		}
+		if fetchedClients != tt.wantClients {
+			t.Errorf("%s: fetched the clients = %v, want %v", tt.name, fetchedClients, tt.wantClients)
+		}
+	}
+}
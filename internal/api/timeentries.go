@@ -2,117 +2,204 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"net/url"
 	"time"
 )
 
 // CreateTimeEntry creates a new time entry in Clockify
-// Takes all the necessary parameters and returns an error if creation fails
-func (c *Client) CreateTimeEntry(workspaceID, projectID, description, timeRange string, date time.Time) error {
-	// Parse the time range string (e.g., "9a - 5p") into actual times
-	startTime, endTime, err := parseTimeRange(timeRange, date)
+// Returns an error if creation fails
+// Callers resolve user input (e.g., "9a - 5p" or "2h") to start/end with the timeparse package
+func (c *Client) CreateTimeEntry(ctx context.Context, workspaceID string, input TimeEntryInput) error {
+	entry := buildTimeEntryRequest(input)
+
+	// Build endpoint and make POST request
+	endpoint := fmt.Sprintf("/workspaces/%s/time-entries", workspaceID)
+	_, err := c.post(ctx, endpoint, entry)
 	if err != nil {
-		return fmt.Errorf("invalid time range: %w", err)
+		return fmt.Errorf("failed to create time entry: %w", err)
 	}
 
-	// Build the request payload
-	entry := TimeEntryRequest{
-		Start:       startTime.Format(time.RFC3339), // Convert to RFC3339 format
-		End:         endTime.Format(time.RFC3339),
-		ProjectID:   projectID,
-		Description: description,
+	return nil
+}
+
+// UpdateTimeEntry replaces an existing time entry with new values
+// Clockify's PUT endpoint expects the full entry, not just changed fields
+func (c *Client) UpdateTimeEntry(ctx context.Context, workspaceID, entryID string, input TimeEntryInput) error {
+	entry := buildTimeEntryRequest(input)
+
+	endpoint := fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID)
+	_, err := c.put(ctx, endpoint, entry)
+	if err != nil {
+		return fmt.Errorf("failed to update time entry: %w", err)
 	}
 
-	// Build endpoint and make POST request
-	endpoint := fmt.Sprintf("/workspaces/%s/time-entries", workspaceID)
-	_, err = c.post(endpoint, entry)
+	return nil
+}
+
+// DeleteTimeEntry permanently removes a time entry
+func (c *Client) DeleteTimeEntry(ctx context.Context, workspaceID, entryID string) error {
+	endpoint := fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID)
+	_, err := c.delete(ctx, endpoint)
 	if err != nil {
-		return fmt.Errorf("failed to create time entry: %w", err)
+		return fmt.Errorf("failed to delete time entry: %w", err)
 	}
 
 	return nil
 }
 
-// GetTasks fetches previous time entries and extracts unique task descriptions
-// This gives us autocomplete suggestions for the user
-func (c *Client) GetTasks(workspaceID, userID string) ([]string, error) {
-	// Build endpoint for user's time entries
+// GetTimeEntries fetches the user's time entries that start within [start, end)
+func (c *Client) GetTimeEntries(ctx context.Context, workspaceID, userID string, start, end time.Time) ([]TimeEntryResponse, error) {
+	// Clockify expects UTC timestamps in the form 2006-01-02T15:04:05Z
+	query := url.Values{}
+	query.Set("start", start.UTC().Format("2006-01-02T15:04:05Z"))
+	query.Set("end", end.UTC().Format("2006-01-02T15:04:05Z"))
 	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID)
-	
-	// Make GET request
-	body, err := c.get(endpoint)
+
+	// A busy week can easily be more than one page
+	entries, err := getAllPages[TimeEntryResponse](ctx, c, endpoint, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// StartTimer creates a time entry with no end, which Clockify treats as a running timer
+// input.End is ignored; returns the new entry so the caller knows exactly when it started
+func (c *Client) StartTimer(ctx context.Context, workspaceID string, input TimeEntryInput) (*TimeEntryResponse, error) {
+	input.End = time.Time{}
+	entry := buildTimeEntryRequest(input)
+
+	endpoint := fmt.Sprintf("/workspaces/%s/time-entries", workspaceID)
+	body, err := c.post(ctx, endpoint, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	var created TimeEntryResponse
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse time entry: %w", err)
+	}
+
+	return &created, nil
+}
+
+// StopTimer stops the user's currently running timer at the given time
+func (c *Client) StopTimer(ctx context.Context, workspaceID, userID string, end time.Time) error {
+	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID)
+	_, err := c.patch(ctx, endpoint, StopTimerRequest{End: end.Format(time.RFC3339)})
+	if err != nil {
+		return fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return nil
+}
+
+// GetRunningTimeEntry returns the user's running timer, or nil if none is running
+func (c *Client) GetRunningTimeEntry(ctx context.Context, workspaceID, userID string) (*TimeEntryResponse, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?in-progress=true", workspaceID, userID)
+
+	body, err := c.get(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse response into slice of time entries
 	var entries []TimeEntryResponse
 	if err := json.Unmarshal(body, &entries); err != nil {
 		return nil, fmt.Errorf("failed to parse time entries: %w", err)
 	}
 
-	// Extract unique task descriptions using a map as a set
-	taskMap := make(map[string]bool)
-	var tasks []string
-	
+	// Clockify only allows one running timer per user
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// LastEndTime returns when the latest finished entry ended, skipping excludeID
+// The bool is false when there are no finished entries to anchor to
+func LastEndTime(entries []TimeEntryResponse, excludeID string) (time.Time, bool) {
+	var last time.Time
 	for _, entry := range entries {
-		// Only add non-empty descriptions that we haven't seen before
-		if entry.Description != "" && !taskMap[entry.Description] {
-			taskMap[entry.Description] = true
-			tasks = append(tasks, entry.Description)
+		// Running timers have no end yet
+		if entry.ID == excludeID || entry.TimeInterval.End.IsZero() {
+			continue
+		}
+		if entry.TimeInterval.End.After(last) {
+			last = entry.TimeInterval.End
 		}
 	}
-
-	return tasks, nil
+	return last, !last.IsZero()
 }
 
-// parseTimeRange splits a time range string like "9a - 5p" into start and end times
-func parseTimeRange(timeRange string, date time.Time) (time.Time, time.Time, error) {
-	// Split on the dash separator
-	parts := strings.Split(timeRange, "-")
-	if len(parts) != 2 {
-		return time.Time{}, time.Time{}, fmt.Errorf("expected format: '9a - 5p'")
+// buildTimeEntryRequest builds the payload for create, update and starting a timer
+func buildTimeEntryRequest(input TimeEntryInput) TimeEntryRequest {
+	entry := TimeEntryRequest{
+		Start:       input.Start.Format(time.RFC3339), // Convert to RFC3339 format
+		ProjectID:   input.ProjectID,
+		TaskID:      input.TaskID,
+		Description: input.Description,
+		Billable:    input.Billable,
+		TagIDs:      input.TagIDs,
 	}
 
-	// Parse each part into a time
-	startTime := parseTime(strings.TrimSpace(parts[0]), date)
-	endTime := parseTime(strings.TrimSpace(parts[1]), date)
+	// No end means a running timer
+	if !input.End.IsZero() {
+		entry.End = input.End.Format(time.RFC3339)
+	}
 
-	return startTime, endTime, nil
+	return entry
 }
 
-// parseTime converts a time string like "9a" or "3:30p" to a full time.Time
-// It handles various formats: 9a, 9:30a, 9, 9:30
-func parseTime(timeStr string, date time.Time) time.Time {
-	// Normalize the string: lowercase, remove spaces
-	timeStr = strings.ToLower(strings.TrimSpace(timeStr))
-	timeStr = strings.ReplaceAll(timeStr, " ", "")
-
-	var hour, minute int
-	
-	// Check if PM (afternoon/evening)
-	isPM := strings.HasSuffix(timeStr, "p") || strings.HasSuffix(timeStr, "pm")
-	
-	// Remove the am/pm suffix
-	timeStr = strings.TrimSuffix(strings.TrimSuffix(timeStr, "p"), "m")
-	timeStr = strings.TrimSuffix(strings.TrimSuffix(timeStr, "a"), "m")
-
-	// Parse hour and optional minutes
-	if strings.Contains(timeStr, ":") {
-		fmt.Sscanf(timeStr, "%d:%d", &hour, &minute)
-	} else {
-		fmt.Sscanf(timeStr, "%d", &hour)
-	}
+// recentEntryLimit caps how much history GetTasks reads for suggestions
+// Entries come newest first, so this is the most recent N entries
+const recentEntryLimit = 1000
 
-	// Convert to 24-hour format
-	if isPM && hour != 12 {
-		hour += 12 // 1pm = 13, 2pm = 14, etc.
-	} else if !isPM && hour == 12 {
-		hour = 0 // 12am = midnight = 0
+// GetTasks fetches previous time entries and tallies how each task description was used
+// This gives us autocomplete suggestions for the user, ranked by the UI
+// There's one TaskUsage per description and project, most recently used first
+func (c *Client) GetTasks(ctx context.Context, workspaceID, userID string) ([]TaskUsage, error) {
+	// Build endpoint for user's time entries
+	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID)
+
+	// Index of each description/project pair in tasks
+	type usageKey struct{ description, projectID string }
+	index := make(map[usageKey]int)
+	var tasks []TaskUsage
+	seen := 0
+
+	// Stream pages rather than loading years of history at once
+	err := forEachPage(ctx, c, endpoint, nil, func(entries []TimeEntryResponse) bool {
+		for _, entry := range entries {
+			// Empty descriptions aren't worth suggesting
+			if entry.Description == "" {
+				continue
+			}
+
+			key := usageKey{entry.Description, entry.ProjectID}
+			if i, ok := index[key]; ok {
+				tasks[i].Count++
+				continue
+			}
+
+			// Entries come newest first, so the first one seen is the latest
+			index[key] = len(tasks)
+			tasks = append(tasks, TaskUsage{
+				Description: entry.Description,
+				ProjectID:   entry.ProjectID,
+				Count:       1,
+				LastUsed:    entry.TimeInterval.Start,
+			})
+		}
+		seen += len(entries)
+		return seen < recentEntryLimit
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent tasks: %w", err)
 	}
 
-	// Combine the date with our parsed time
-	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+	return tasks, nil
 }
@@ -1,31 +1,110 @@
 // Defines data structures that match the Clockify API responses
 package api
 
+import "time"
+
 // UserInfo contains information about the current user
 type UserInfo struct {
 	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
 	DefaultWorkspace string `json:"defaultWorkspace"`
 }
 
+// Workspace is a Clockify workspace - consultants often belong to one per client
+type Workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // Project represents a Clockify project
 // The json tags tell Go how to map JSON fields to struct fields
 type Project struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ClientID   string `json:"clientId"`
+	ClientName string `json:"clientName"`
+	Billable   bool   `json:"billable"` // Default billable status for new entries
+}
+
+// ProjectTask is a task inside a Clockify project (not to be confused with
+// the free-text description, which this tool calls the task elsewhere)
+type ProjectTask struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ProjectID string `json:"projectId"`
+}
+
+// Customer is what Clockify calls a "client" - the company a project is for
+// It's named Customer so it doesn't clash with our API Client type
+type Customer struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
-	ClientID string `json:"clientId"`
-	ClientName string `json:"clientName"`
+}
+
+// Tag is a workspace-wide label that can be attached to time entries
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TagRequest is the payload we send when creating a tag
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+// TimeEntryInput holds everything the user chose for an entry
+// The client turns it into a TimeEntryRequest when talking to the API
+type TimeEntryInput struct {
+	ProjectID   string
+	TaskID      string // Optional Clockify task within the project
+	Description string
+	Billable    bool
+	Start       time.Time
+	End         time.Time // Zero for a running timer
+	TagIDs      []string
 }
 
 // TimeEntryRequest is the payload we send when creating a time entry
 type TimeEntryRequest struct {
-	Start       string `json:"start"`       // RFC3339 format timestamp
-	End         string `json:"end"`         // RFC3339 format timestamp
-	ProjectID   string `json:"projectId"`   // ID of the project
-	Description string `json:"description"` // Task description
+	Start       string   `json:"start"`            // RFC3339 format timestamp
+	End         string   `json:"end,omitempty"`    // RFC3339 format timestamp - omitted to start a timer
+	ProjectID   string   `json:"projectId"`        // ID of the project
+	TaskID      string   `json:"taskId,omitempty"` // ID of the project's task, if any
+	Description string   `json:"description"`      // Task description
+	Billable    bool     `json:"billable"`         // Whether the entry is billable
+	TagIDs      []string `json:"tagIds,omitempty"` // IDs of tags to attach
+}
+
+// TaskUsage is how a task description was used on one project
+// GetTasks returns these so suggestions can favour the current project
+type TaskUsage struct {
+	Description string
+	ProjectID   string
+	Count       int       // Entries with this description on this project
+	LastUsed    time.Time // Start of the most recent of those entries
+}
+
+// StopTimerRequest is the payload that stops the user's running timer
+type StopTimerRequest struct {
+	End string `json:"end"` // RFC3339 format timestamp
 }
 
 // TimeEntryResponse represents a time entry returned from the API
-// We use this to parse previous entries and extract task descriptions
+// We use this to list, edit and delete entries, and to extract task descriptions
 type TimeEntryResponse struct {
-	Description string `json:"description"`
+	ID           string       `json:"id"`
+	Description  string       `json:"description"`
+	ProjectID    string       `json:"projectId"`
+	TaskID       string       `json:"taskId"`
+	Billable     bool         `json:"billable"`
+	TagIDs       []string     `json:"tagIds"`
+	TimeInterval TimeInterval `json:"timeInterval"`
+}
+
+// TimeInterval holds the start and end of a time entry
+// End is the zero time while a timer is still running
+type TimeInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
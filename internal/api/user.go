@@ -2,6 +2,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -9,9 +10,9 @@ import (
 // GetUserInfo fetches the current user's information from Clockify
 // This includes their user ID and default workspace ID
 // Returns UserInfo or an error if the request fails
-func (c *Client) GetUserInfo() (*UserInfo, error) {
+func (c *Client) GetUserInfo(ctx context.Context) (*UserInfo, error) {
 	// Make a GET request to /user endpoint
-	body, err := c.get("/user")
+	body, err := c.get(ctx, "/user")
 	if err != nil {
 		return nil, err
 	}
@@ -24,4 +25,3 @@ func (c *Client) GetUserInfo() (*UserInfo, error) {
 
 	return &user, nil
 }
-
@@ -0,0 +1,36 @@
+// Functions for listing the workspaces a user belongs to
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GetWorkspaces fetches every workspace the user is a member of
+func (c *Client) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
+	body, err := c.get(ctx, "/workspaces")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch workspaces: %w", err)
+	}
+
+	var workspaces []Workspace
+	if err := json.Unmarshal(body, &workspaces); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// FindWorkspace looks up a workspace by ID or by name, ignoring case
+// so a configured workspace can be written either way
+func FindWorkspace(workspaces []Workspace, key string) (Workspace, bool) {
+	key = strings.TrimSpace(key)
+	for _, ws := range workspaces {
+		if ws.ID == key || strings.EqualFold(ws.Name, key) {
+			return ws, true
+		}
+	}
+	return Workspace{}, false
+}
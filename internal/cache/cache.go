@@ -0,0 +1,89 @@
+// Package cache keeps copies of Clockify data on disk, so the UI can show
+// the last known projects right away while fresh ones load in the background
+// Each value is a JSON file under the config dir, stamped with when it was saved
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"clockify-time-tracker/internal/utils"
+)
+
+// stored is the layout of a cache file
+type stored[T any] struct {
+	SavedAt time.Time `json:"savedAt"`
+	Value   T         `json:"value"`
+}
+
+// Key joins parts into a file-safe cache key, e.g. Key("projects", workspaceID)
+func Key(parts ...string) string {
+	return strings.Join(parts, "-")
+}
+
+// AccountKey is a cache key for data that depends on the API key (e.g., user info)
+// The key is hashed so it never ends up in a file name
+func AccountKey(apiKey, baseURL string) string {
+	sum := sha256.Sum256([]byte(baseURL + "\n" + apiKey))
+	return Key("user", hex.EncodeToString(sum[:8]))
+}
+
+// Load returns the value saved under key if it's younger than ttl
+// Anything missing, expired or unreadable is simply a miss - the caller fetches instead
+func Load[T any](key string, ttl time.Duration) (T, bool) {
+	var zero T
+	if ttl <= 0 {
+		return zero, false
+	}
+
+	s, ok := read[T](key)
+	if !ok || time.Since(s.SavedAt) > ttl {
+		return zero, false
+	}
+	return s.Value, true
+}
+
+// LoadStale returns the value saved under key however old it is
+// It's for when Clockify can't be reached, where old data beats none
+func LoadStale[T any](key string) (T, bool) {
+	s, ok := read[T](key)
+	return s.Value, ok
+}
+
+// read loads the cache file for key; a missing or unreadable file is a miss
+func read[T any](key string) (stored[T], bool) {
+	var s stored[T]
+	data, err := os.ReadFile(path(key))
+	if err != nil {
+		return s, false
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return stored[T]{}, false
+	}
+	return s, true
+}
+
+// Save stores value under key, replacing the file in one step
+// so a crash mid-write can't leave it half written
+func Save[T any](key string, value T) error {
+	data, err := json.Marshal(stored[T]{SavedAt: time.Now(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(path(key), data, 0o600); err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+	return nil
+}
+
+// path is where the value for key is stored
+func path(key string) string {
+	return filepath.Join(utils.ConfigDir(), "cache", key+".json")
+}
@@ -0,0 +1,79 @@
+// Implements the `config` subcommand, which reads and changes config.toml
+// so settings can be managed without opening the file
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"clockify-time-tracker/internal/utils"
+)
+
+// configUsage explains the `config` subcommand
+const configUsage = `Usage:
+  clockify-tracker config [list]         show every setting and its value
+  clockify-tracker config get <key>      print one setting
+  clockify-tracker config set <key> <value>
+  clockify-tracker config path           print where the config file is`
+
+// RunConfig handles `config list|get|set|path`
+// It doesn't need an API key, so a broken setup can still be fixed with it
+func RunConfig(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		list, err := utils.ListConfig()
+		if err != nil {
+			return err
+		}
+		for _, s := range list {
+			value := "(not set)"
+			if s.Set {
+				value = fmt.Sprintf("%q", s.Value)
+			}
+			fmt.Fprintf(out, "%-16s %-20s # %s\n", s.Key, value, s.Help)
+		}
+		return nil
+
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("expected one key\n%s", configUsage)
+		}
+		value, ok, err := utils.GetConfigValue(args[1])
+		if err != nil {
+			return err
+		}
+		// Non-zero exit lets scripts tell "unset" apart from an empty value
+		if !ok {
+			return fmt.Errorf("%s is not set", args[1])
+		}
+		fmt.Fprintln(out, value)
+		return nil
+
+	case "set":
+		if len(args) < 3 {
+			return fmt.Errorf("expected a key and a value\n%s", configUsage)
+		}
+		// Allow unquoted values with spaces, e.g. `config set time_range 9a - 5p`
+		value := strings.Join(args[2:], " ")
+		if err := utils.SetConfigValue(args[1], value); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Set %s = %q in %s\n", args[1], value, utils.ConfigFile())
+		return nil
+
+	case "path":
+		fmt.Fprintln(out, utils.ConfigFile())
+		return nil
+
+	case "-h", "--help", "help":
+		fmt.Fprintln(out, configUsage)
+		return nil
+	}
+
+	return fmt.Errorf("unknown config command %q\n%s", args[0], configUsage)
+}
@@ -0,0 +1,317 @@
+// Implements the non-interactive `log` subcommand
+// This lets scripts and cron jobs create time entries without the TUI
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cache"
+	"clockify-time-tracker/internal/queue"
+	"clockify-time-tracker/internal/timeparse"
+	"clockify-time-tracker/internal/utils"
+)
+
+// dateLayout is the format accepted by the --date flag (e.g., 2026-10-17)
+const dateLayout = "2006-01-02"
+
+// ErrQueued means the entry was saved offline instead of reaching Clockify
+// It's already been explained on out, and main exits with its own code so
+// scripts can tell it apart from both success and failure
+var ErrQueued = errors.New("entry saved offline")
+
+// RunLog parses the `log` subcommand flags and creates a single time entry
+// Any problem is returned as an error so main can exit non-zero
+// Cancelling ctx (e.g., on ctrl+c) aborts any request in flight
+func RunLog(ctx context.Context, config *utils.Config, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.SetOutput(out)
+	location := config.Location
+	if location == nil {
+		location = time.Local
+	}
+	dateStr := fs.String("date", time.Now().In(location).Format(dateLayout), "date of the entry (YYYY-MM-DD)")
+	projectName := fs.String("project", config.Project, "project name (case-insensitive; defaults to project in config.toml)")
+	timeRange := fs.String("time", config.TimeRange, "time range or duration, e.g. \"9a - 5p\" or \"2h\" (defaults to time_range in config.toml)")
+	task := fs.String("task", "", "task description")
+	workspaceKey := fs.String("workspace", config.Workspace, "workspace ID or name (defaults to CLOCKIFY_WORKSPACE, then your default workspace)")
+	billable := fs.String("billable", "", "true or false (defaults to the project's setting)")
+	if err := fs.Parse(args); err != nil {
+		// -h prints usage and is not a failure
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	// All of these are required - there is nobody to prompt for them
+	if *projectName == "" {
+		return fmt.Errorf("--project is required")
+	}
+	if *timeRange == "" {
+		return fmt.Errorf("--time is required")
+	}
+	if *task == "" {
+		return fmt.Errorf("--task is required")
+	}
+
+	// Parse the date in the configured time zone so the entry lands on the expected day
+	date, err := time.ParseInLocation(dateLayout, *dateStr, location)
+	if err != nil {
+		return fmt.Errorf("invalid --date %q (expected YYYY-MM-DD)", *dateStr)
+	}
+
+	// Without a default key there's no picker to fall back on, so a profile must be named
+	if config.APIKey == "" {
+		return fmt.Errorf("no default CLOCKIFY_API_KEY; pick a profile with --profile (available: %s)", strings.Join(config.Profiles, ", "))
+	}
+
+	conn := &connection{client: api.NewClient(config.APIKey, config.ClientOptions()...), config: config}
+	userInfo, err := fetchUserInfo(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	workspaceID, err := resolveWorkspace(ctx, conn, &userInfo, *workspaceKey)
+	if err != nil {
+		return err
+	}
+
+	project, err := resolveProject(ctx, conn, workspaceID, *projectName)
+	if err != nil {
+		return err
+	}
+
+	// Use the project's default unless the flag says otherwise
+	isBillable := project.Billable
+	if *billable != "" {
+		isBillable, err = strconv.ParseBool(*billable)
+		if err != nil {
+			return fmt.Errorf("invalid --billable %q (expected true or false)", *billable)
+		}
+	}
+
+	start, end, err := resolveTimeRange(ctx, conn, workspaceID, userInfo.ID, *timeRange, date)
+	if err != nil {
+		return err
+	}
+
+	entry := api.TimeEntryInput{ProjectID: project.ID, Description: *task, Billable: isBillable, Start: start, End: end}
+	if conn.err == nil {
+		if err := conn.client.CreateTimeEntry(ctx, workspaceID, entry); err != nil {
+			if !api.IsTemporary(err) {
+				return err
+			}
+			conn.err = err
+		}
+	}
+
+	// Offline or Clockify is struggling - keep the entry rather than losing it
+	if conn.err != nil {
+		pending, queueErr := queue.Add(userInfo.ID, workspaceID, entry, conn.err)
+		if queueErr != nil {
+			return fmt.Errorf("%w (and saving it offline failed: %v)", conn.err, queueErr)
+		}
+		fmt.Fprintf(out, "Clockify couldn't be reached (%v)\nSaved the entry offline, %d pending - run `clockify-tracker sync` to send it\n", conn.err, pending)
+		return ErrQueued
+	}
+
+	fmt.Fprintf(out, "Logged %s on %s to %s: %s\n", timeparse.FormatRange(start, end), date.Format("Jan 2, 2006"), project.Name, *task)
+	return nil
+}
+
+// connection tracks whether Clockify can be reached during a `log` run
+// After the first network failure the rest of the run works from saved data,
+// instead of waiting for every remaining request to time out
+type connection struct {
+	client *api.Client
+	config *utils.Config
+	err    error // Why Clockify couldn't be reached; nil while it can
+}
+
+// fetchOrCached fetches a value and saves it under key for later offline runs
+// When Clockify can't be reached, the last saved copy is used however old it is
+func fetchOrCached[T any](conn *connection, key string, fetch func() (T, error)) (T, error) {
+	if conn.err == nil {
+		value, err := fetch()
+		if err == nil {
+			if conn.config.CacheTTL > 0 {
+				_ = cache.Save(key, value) // A failed write only matters on a later offline run
+			}
+			return value, nil
+		}
+		if !api.IsTemporary(err) {
+			return value, err
+		}
+		conn.err = err
+	}
+
+	var zero T
+	if conn.config.CacheTTL <= 0 {
+		return zero, conn.err
+	}
+	value, ok := cache.LoadStale[T](key)
+	if !ok {
+		return zero, fmt.Errorf("%w (and nothing is saved from an earlier run)", conn.err)
+	}
+	return value, nil
+}
+
+// fetchUserInfo fetches the user behind the API key, or the copy saved by an
+// earlier run when Clockify can't be reached
+func fetchUserInfo(ctx context.Context, conn *connection) (api.UserInfo, error) {
+	userInfo, err := fetchOrCached(conn, cache.AccountKey(conn.config.APIKey, conn.config.BaseURL), func() (api.UserInfo, error) {
+		userInfo, err := conn.client.GetUserInfo(ctx)
+		if err != nil {
+			return api.UserInfo{}, err
+		}
+		return *userInfo, nil
+	})
+	if err != nil {
+		return api.UserInfo{}, fmt.Errorf("failed to fetch user info: %w", err)
+	}
+	return userInfo, nil
+}
+
+// resolveTimeRange turns --time into start/end times
+// Durations start after the last entry on that date, or at the configured day start
+// Entries still in the offline queue count too, since they'll end up in Clockify
+func resolveTimeRange(ctx context.Context, conn *connection, workspaceID, userID, input string, date time.Time) (time.Time, time.Time, error) {
+	anchor, err := timeparse.ParseTime(conn.config.DayStart, date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	// Only a duration needs to know what's already logged
+	if !timeparse.IsRange(input) {
+		var entries []api.TimeEntryResponse
+		if conn.err == nil {
+			entries, err = conn.client.GetTimeEntries(ctx, workspaceID, userID, date, date.AddDate(0, 0, 1))
+			if err != nil && !api.IsTemporary(err) {
+				return time.Time{}, time.Time{}, fmt.Errorf("failed to fetch entries for %s: %w", date.Format(dateLayout), err)
+			}
+			if err != nil {
+				conn.err = err
+			}
+		}
+		queued, err := queue.Load(userID)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		nextDay := date.AddDate(0, 0, 1)
+		for _, item := range queued {
+			if item.WorkspaceID == workspaceID && !item.Entry.Start.Before(date) && item.Entry.Start.Before(nextDay) {
+				entries = append(entries, api.TimeEntryResponse{ID: item.ID, TimeInterval: api.TimeInterval{Start: item.Entry.Start, End: item.Entry.End}})
+			}
+		}
+		if last, ok := api.LastEndTime(entries, ""); ok {
+			anchor = last.In(date.Location())
+		}
+	}
+
+	start, end, err := timeparse.ParseInput(input, date, anchor)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --time: %w", err)
+	}
+	return start, end, nil
+}
+
+// resolveWorkspace turns --workspace into a workspace ID
+// Without one, the user's default workspace is used
+func resolveWorkspace(ctx context.Context, conn *connection, userInfo *api.UserInfo, key string) (string, error) {
+	if key == "" {
+		return userInfo.DefaultWorkspace, nil
+	}
+
+	workspaces, err := fetchOrCached(conn, cache.Key("workspaces", userInfo.ID), func() ([]api.Workspace, error) {
+		return conn.client.GetWorkspaces(ctx)
+	})
+	if err != nil {
+		// Offline with nothing saved - an ID can still be used as is
+		if conn.err != nil && isClockifyID(key) {
+			return key, nil
+		}
+		return "", err
+	}
+
+	ws, ok := api.FindWorkspace(workspaces, key)
+	if !ok {
+		return "", fmt.Errorf("no workspace matches %q", key)
+	}
+	return ws.ID, nil
+}
+
+// resolveProject turns --project into one of the workspace's projects
+// Offline with no saved project list, a project ID is accepted without a lookup
+func resolveProject(ctx context.Context, conn *connection, workspaceID, name string) (api.Project, error) {
+	projects, err := fetchOrCached(conn, cache.Key("projects", workspaceID), func() ([]api.Project, error) {
+		return conn.client.GetProjects(ctx, workspaceID)
+	})
+	if err != nil {
+		if conn.err != nil && isClockifyID(name) {
+			return api.Project{ID: name, Name: name}, nil
+		}
+		if conn.err != nil {
+			return api.Project{}, fmt.Errorf("failed to fetch projects: %w - pass a project ID to log without them", err)
+		}
+		return api.Project{}, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	// A saved list can miss a project created since, so trust an ID offline
+	project, err := findProject(projects, name)
+	if err != nil && conn.err != nil && isClockifyID(name) {
+		return api.Project{ID: name, Name: name}, nil
+	}
+	return project, err
+}
+
+// isClockifyID reports whether s looks like a Clockify ID (24 hex digits)
+func isClockifyID(s string) bool {
+	if len(s) != 24 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// findProject looks up a project by ID or name, ignoring case
+// An exact match wins; otherwise a single partial match is accepted
+func findProject(projects []api.Project, name string) (api.Project, error) {
+	query := strings.ToLower(strings.TrimSpace(name))
+
+	var partial []api.Project
+	for _, proj := range projects {
+		projName := strings.ToLower(proj.Name)
+		if projName == query || proj.ID == strings.TrimSpace(name) {
+			return proj, nil
+		}
+		if strings.Contains(projName, query) {
+			partial = append(partial, proj)
+		}
+	}
+
+	switch len(partial) {
+	case 0:
+		return api.Project{}, fmt.Errorf("no project matches %q", name)
+	case 1:
+		return partial[0], nil
+	}
+
+	// Several partial matches - list them so the user can be more specific
+	names := make([]string, len(partial))
+	for i, proj := range partial {
+		names[i] = proj.Name
+	}
+	return api.Project{}, fmt.Errorf("project %q is ambiguous, matches: %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,90 @@
+// Implements the `login` subcommand, which checks an API key and saves it
+// in the credential store so it doesn't have to sit in a plaintext .env
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/utils"
+)
+
+// RunLogin asks for an API key, validates it with Clockify and saves it
+// profile is the --profile given before the subcommand ("" for CLOCKIFY_PROFILE or the default account)
+// It runs before LoadConfig, since there may not be a key to load yet
+func RunLogin(ctx context.Context, profile string, args []string, out io.Writer) error {
+	utils.LoadEnv()
+	if profile == "" {
+		profile = os.Getenv("CLOCKIFY_PROFILE")
+	}
+
+	configured, err := utils.StoreKind()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	fs.SetOutput(out)
+	kind := fs.String("store", configured, "where to save the key: auto, keyring, file or env (defaults to credential_store in config.toml)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	// Check the store can be used before asking for anything
+	if _, err := utils.OpenCredentialStore(*kind); err != nil {
+		return err
+	}
+
+	// An existing profile may point at another server
+	baseURL := os.Getenv("CLOCKIFY_BASE_URL")
+	if profile != "" && utils.HasProfile(profile) {
+		p, err := utils.LoadProfile(profile)
+		if err != nil {
+			return err
+		}
+		baseURL = p.BaseURL
+	}
+
+	apiKey, err := utils.ReadSecret("Clockify API key (from https://clockify.me/user/settings): ")
+	if err != nil {
+		return err
+	}
+	if apiKey == "" {
+		return fmt.Errorf("no API key given")
+	}
+
+	// Only save a key Clockify accepts
+	userInfo, err := api.NewClient(apiKey, api.WithBaseURL(baseURL)).GetUserInfo(ctx)
+	if err != nil {
+		if api.IsAuth(err) {
+			return fmt.Errorf("Clockify rejected this API key")
+		}
+		return fmt.Errorf("failed to check the API key: %w", err)
+	}
+
+	store, removed, err := utils.SaveAPIKey(profile, *kind, apiKey)
+	if err != nil {
+		return err
+	}
+
+	// Remember a store picked on the command line, so the key is looked up there
+	if *kind != configured {
+		if err := utils.SetConfigValue("credential_store", *kind); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Logged in as %s <%s>; API key saved to the %s\n", userInfo.Name, userInfo.Email, store.Name())
+	if removed {
+		fmt.Fprintln(out, "Removed the plaintext CLOCKIFY_API_KEY from the env file")
+	}
+	return nil
+}
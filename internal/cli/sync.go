@@ -0,0 +1,69 @@
+// Implements the `sync` subcommand, which sends entries saved while offline
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/queue"
+	"clockify-time-tracker/internal/timeparse"
+	"clockify-time-tracker/internal/utils"
+)
+
+// RunSync sends the offline queue to Clockify, or lists it with --list
+func RunSync(ctx context.Context, config *utils.Config, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(out)
+	list := fs.Bool("list", false, "only list the queued entries")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if config.APIKey == "" {
+		return fmt.Errorf("no default CLOCKIFY_API_KEY; pick a profile with --profile")
+	}
+	// Listing only needs to know whose queue it is, so saved user info will do offline
+	conn := &connection{client: api.NewClient(config.APIKey, config.ClientOptions()...), config: config}
+	userInfo, err := fetchUserInfo(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		items, err := queue.Load(userInfo.ID)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Fprintln(out, "Nothing queued")
+			return nil
+		}
+		for _, item := range items {
+			start := item.Entry.Start.In(config.Location)
+			fmt.Fprintf(out, "%s  %s %s: %s\n    last error: %s\n", item.ID, start.Format("Jan 2, 2006"),
+				timeparse.FormatRange(start, item.Entry.End.In(config.Location)), item.Entry.Description, item.Reason)
+		}
+		return nil
+	}
+
+	// Sending needs Clockify, which just turned out to be unreachable
+	if conn.err != nil {
+		return fmt.Errorf("failed to fetch user info: %w", conn.err)
+	}
+	result, err := queue.Flush(ctx, conn.client, userInfo.ID)
+	if errors.Is(err, queue.ErrBusy) {
+		return err
+	}
+	fmt.Fprintf(out, "Sent %d, skipped %d already in Clockify, %d still pending\n", result.Sent, result.Duplicates, result.Pending)
+	if err != nil {
+		return fmt.Errorf("%w (run `clockify-tracker sync --list` to see what's queued)", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package fuzzy implements fzf-style subsequence matching for search boxes
+// Every character of the query must appear in the text in order, but not
+// necessarily next to each other, so "acweb" matches "Acme Corp — Website"
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scoring weights - matches at word starts and unbroken runs of characters
+// score higher, and every skipped character in between costs a little
+const (
+	scoreMatch       = 16
+	bonusBoundary    = 8 // Match at the start of a word, e.g. the W in "Corp — Website"
+	bonusFirst       = 8 // Extra for matching the very first character of the text
+	bonusConsecutive = 8 // Match right after the previous one
+	penaltyGap       = 1 // Per character skipped between two matches
+)
+
+// noMatch marks impossible states in the scoring table
+const noMatch = -1 << 30
+
+// Result describes how a query matched a piece of text
+type Result struct {
+	Score     int   // Higher is better; only comparable between results for the same query
+	Positions []int // Rune indexes in the text that matched, in order
+}
+
+// Match reports whether query is a case-insensitive subsequence of text
+// Whitespace in the query is ignored. When there are several ways to match,
+// the best-scoring one is returned so highlighting shows the most natural spot
+func Match(query, text string) (Result, bool) {
+	var pattern []rune
+	for _, r := range strings.ToLower(query) {
+		if !unicode.IsSpace(r) {
+			pattern = append(pattern, r)
+		}
+	}
+	if len(pattern) == 0 {
+		return Result{}, true
+	}
+
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Case folding changed the length (rare) - fall back to the raw runes
+		lower = runes
+	}
+	if len(pattern) > len(runes) {
+		return Result{}, false
+	}
+
+	// score[i][j] is the best score for matching pattern[:i+1] with pattern[i] at text j
+	// from[i][j] remembers where pattern[i-1] matched, to recover the positions
+	score := make([][]int, len(pattern))
+	from := make([][]int, len(pattern))
+	for i := range pattern {
+		score[i] = make([]int, len(runes))
+		from[i] = make([]int, len(runes))
+		for j := range runes {
+			score[i][j] = noMatch
+		}
+	}
+
+	for i, p := range pattern {
+		// best is the best score for pattern[i-1] matched at k <= j-2,
+		// already reduced by the gap penalty up to j
+		best, bestAt := noMatch, -1
+
+		for j := i; j < len(runes); j++ {
+			if i > 0 && j >= 2 && score[i-1][j-2] != noMatch {
+				if candidate := score[i-1][j-2] - penaltyGap; candidate > best-penaltyGap {
+					best, bestAt = candidate, j-2
+				} else {
+					best -= penaltyGap
+				}
+			} else if best != noMatch {
+				best -= penaltyGap
+			}
+
+			if lower[j] != p {
+				continue
+			}
+
+			gain := scoreMatch + bonus(runes, j)
+			if i == 0 {
+				score[i][j] = gain
+				continue
+			}
+
+			// Either extend a run from the previous character, or jump a gap
+			prev, prevAt := best, bestAt
+			if score[i-1][j-1] != noMatch && score[i-1][j-1]+bonusConsecutive > prev {
+				prev, prevAt = score[i-1][j-1]+bonusConsecutive, j-1
+			}
+			if prev == noMatch {
+				continue
+			}
+			score[i][j] = prev + gain
+			from[i][j] = prevAt
+		}
+	}
+
+	// Pick the best place for the last character and walk back from there
+	last := len(pattern) - 1
+	end := -1
+	for j := range runes {
+		if score[last][j] != noMatch && (end < 0 || score[last][j] > score[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	positions := make([]int, len(pattern))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+
+	return Result{Score: score[last][end], Positions: positions}, true
+}
+
+// bonus rewards matching at the start of a word: the first character, after a
+// space or punctuation, or a capital letter following a lower-case one
+func bonus(runes []rune, j int) int {
+	if j == 0 {
+		return bonusBoundary + bonusFirst
+	}
+	prev, cur := runes[j-1], runes[j]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return bonusBoundary
+	}
+	if unicode.IsLower(prev) && unicode.IsUpper(cur) {
+		return bonusBoundary
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		positions   []int
+	}{
+		{"", "Anything", nil},
+		{"web", "Website", []int{0, 1, 2}},
+		{"WEB", "website", []int{0, 1, 2}},
+		{"acweb", "Acme Corp — Website", []int{0, 1, 12, 13, 14}}, // "Ac" in a row beats two word starts
+		{"ac web", "Acme Corp — Website", []int{0, 1, 12, 13, 14}},
+		{"cw", "Acme Corp — Website", []int{5, 12}},
+		{"api", "BackendAPI", []int{7, 8, 9}},
+		{"ib", "Internal Billing", []int{0, 9}},
+		{"ü", "Müller", []int{1}},
+	}
+	for _, tt := range tests {
+		result, ok := Match(tt.query, tt.text)
+		if !ok {
+			t.Errorf("Match(%q, %q) didn't match", tt.query, tt.text)
+			continue
+		}
+		if !reflect.DeepEqual(result.Positions, tt.positions) {
+			t.Errorf("Match(%q, %q) positions = %v, want %v", tt.query, tt.text, result.Positions, tt.positions)
+		}
+	}
+}
+
+func TestMatchRejects(t *testing.T) {
+	tests := []struct {
+		query, text string
+	}{
+		{"bew", "Website"},      // Out of order
+		{"webb", "Website"},     // Too many of a character
+		{"websites", "Website"}, // Longer than the text
+		{"x", ""},
+		{"acme", "Corp"},
+	}
+	for _, tt := range tests {
+		if result, ok := Match(tt.query, tt.text); ok {
+			t.Errorf("Match(%q, %q) = %v, want no match", tt.query, tt.text, result.Positions)
+		}
+	}
+}
+
+func TestMatchRanking(t *testing.T) {
+	// Each list is in the order the texts should rank for the query
+	tests := []struct {
+		query string
+		texts []string
+	}{
+		// Word starts beat the same letters in the middle of a word
+		{"web", []string{"Website", "Company Website", "Cobweb"}},
+		// The start of the text beats a later word, which beats the middle of a word
+		{"api", []string{"API Gateway", "Backend API", "Capital"}},
+		// Fewer skipped characters beat more
+		{"ab", []string{"Ab", "A b", "A long b"}},
+	}
+	for _, tt := range tests {
+		prev := 0
+		for i, text := range tt.texts {
+			result, ok := Match(tt.query, text)
+			if !ok {
+				t.Errorf("Match(%q, %q) didn't match", tt.query, text)
+				break
+			}
+			if i > 0 && result.Score >= prev {
+				t.Errorf("Match(%q, %q) scored %d, want less than %q's %d", tt.query, text, result.Score, tt.texts[i-1], prev)
+			}
+			prev = result.Score
+		}
+	}
+}
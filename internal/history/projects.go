@@ -0,0 +1,156 @@
+// Package history remembers which projects a user picks, so the project list
+// can show favorites and recently used projects first
+// It's stored as JSON next to the config, one file per Clockify user
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"clockify-time-tracker/internal/utils"
+)
+
+// Usage is how often and how recently a project was used
+type Usage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// Projects is one user's favorites and project usage
+type Projects struct {
+	Favorites []string         `json:"favorites"` // Project IDs, in the order they were starred
+	Usage     map[string]Usage `json:"usage"`     // Keyed by project ID
+
+	userID string
+}
+
+// Load reads the user's project history
+// A user who hasn't picked anything yet gets an empty history, not an error
+func Load(userID string) (Projects, error) {
+	p := Projects{Usage: map[string]Usage{}, userID: userID}
+
+	data, err := os.ReadFile(path(userID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to read project history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Projects{Usage: map[string]Usage{}, userID: userID}, fmt.Errorf("failed to parse project history: %w", err)
+	}
+	if p.Usage == nil {
+		p.Usage = map[string]Usage{}
+	}
+	return p, nil
+}
+
+// Save writes the history back to disk, replacing the file in one step
+// so a crash mid-write can't leave it half written
+func (p Projects) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode project history: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(path(p.userID), data, 0o600); err != nil {
+		return fmt.Errorf("failed to save project history: %w", err)
+	}
+	return nil
+}
+
+// Clone returns a copy that shares nothing with p
+// The UI saves clones in the background while it keeps changing its own copy
+func (p Projects) Clone() Projects {
+	clone := Projects{
+		Favorites: append([]string(nil), p.Favorites...),
+		Usage:     make(map[string]Usage, len(p.Usage)),
+		userID:    p.userID,
+	}
+	for id, usage := range p.Usage {
+		clone.Usage[id] = usage
+	}
+	return clone
+}
+
+// Use records that an entry was logged to the project
+func (p *Projects) Use(projectID string, at time.Time) {
+	if p.Usage == nil {
+		p.Usage = map[string]Usage{}
+	}
+	usage := p.Usage[projectID]
+	usage.Count++
+	usage.LastUsed = at
+	p.Usage[projectID] = usage
+}
+
+// IsFavorite reports whether the project is starred
+func (p Projects) IsFavorite(projectID string) bool {
+	for _, id := range p.Favorites {
+		if id == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite stars or unstars the project
+func (p *Projects) ToggleFavorite(projectID string) {
+	var favorites []string
+	for _, id := range p.Favorites {
+		if id != projectID {
+			favorites = append(favorites, id)
+		}
+	}
+	if len(favorites) == len(p.Favorites) {
+		favorites = append(favorites, projectID)
+	}
+	p.Favorites = favorites
+}
+
+// Recent returns up to limit used project IDs, best first
+// Projects used often rank high, but their weight fades the longer they go unused
+func (p Projects) Recent(limit int, now time.Time) []string {
+	ids := make([]string, 0, len(p.Usage))
+	scores := make(map[string]float64, len(p.Usage))
+	for id, usage := range p.Usage {
+		weeks := now.Sub(usage.LastUsed).Hours() / (24 * 7)
+		scores[id] = float64(usage.Count) / (1 + weeks)
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j] // Keep ties in a stable order between runs
+	})
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
+// MostRecent returns the project used last, or "" if none was used yet
+func (p Projects) MostRecent() string {
+	var latest string
+	for id, usage := range p.Usage {
+		if latest == "" || usage.LastUsed.After(p.Usage[latest].LastUsed) {
+			latest = id
+		}
+	}
+	return latest
+}
+
+// path is the history file for a user
+func path(userID string) string {
+	return filepath.Join(utils.ConfigDir(), "history", userID+".json")
+}
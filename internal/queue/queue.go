@@ -0,0 +1,333 @@
+// Package queue keeps time entries that couldn't reach Clockify, e.g. on a
+// train or a flaky VPN, so they aren't lost and can be sent later
+// It's a JSON lines journal next to the config, one file per Clockify user
+package queue
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/utils"
+)
+
+// Item is one entry waiting to be sent
+type Item struct {
+	ID          string    `json:"id"` // Local ID, only used inside the journal
+	WorkspaceID string    `json:"workspaceId"`
+	Entry       Entry     `json:"entry"`
+	QueuedAt    time.Time `json:"queuedAt"`
+	Reason      string    `json:"reason"` // Why the last attempt failed
+}
+
+// Entry is a queued time entry as it's stored in the journal
+// It mirrors api.TimeEntryInput with names of its own, so changing the
+// API types can't make entries already queued unreadable
+type Entry struct {
+	ProjectID   string    `json:"projectId"`
+	TaskID      string    `json:"taskId,omitempty"`
+	Description string    `json:"description"`
+	Billable    bool      `json:"billable"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	TagIDs      []string  `json:"tagIds,omitempty"`
+}
+
+// newEntry copies an entry that failed to send into the journal's format
+func newEntry(input api.TimeEntryInput) Entry {
+	return Entry{
+		ProjectID:   input.ProjectID,
+		TaskID:      input.TaskID,
+		Description: input.Description,
+		Billable:    input.Billable,
+		Start:       input.Start,
+		End:         input.End,
+		TagIDs:      input.TagIDs,
+	}
+}
+
+// Input turns a queued entry back into what CreateTimeEntry takes
+func (e Entry) Input() api.TimeEntryInput {
+	return api.TimeEntryInput{
+		ProjectID:   e.ProjectID,
+		TaskID:      e.TaskID,
+		Description: e.Description,
+		Billable:    e.Billable,
+		Start:       e.Start,
+		End:         e.End,
+		TagIDs:      e.TagIDs,
+	}
+}
+
+// Result sums up a Flush
+type Result struct {
+	Sent       int // Created in Clockify
+	Duplicates int // Already in Clockify, so dropped without sending
+	Pending    int // Still in the journal
+}
+
+// ErrBusy means another process (e.g., `sync` while the app starts) is already
+// sending the queue; whatever it doesn't send stays queued
+var ErrBusy = errors.New("another clockify-tracker is already sending the offline queue")
+
+// How long lock files are trusted before they're treated as left behind by a
+// crashed process. The journal lock is only held while reading and writing the
+// file; the flush lock is refreshed after every entry sent
+const (
+	journalLockStale = 30 * time.Second
+	flushLockStale   = 10 * time.Minute
+	journalLockWait  = 10 * time.Second // How long Add waits for the journal before giving up
+)
+
+// path is where a user's journal is stored
+func path(userID string) string {
+	return filepath.Join(utils.ConfigDir(), "queue", userID+".jsonl")
+}
+
+// lock creates a lock file, so two processes (e.g., the app and a `log` run
+// from cron) can't both change the journal at once
+// With wait false it gives up straight away when the lock is held
+// The returned func releases the lock
+func lock(file string, stale time.Duration, wait bool) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to lock offline queue: %w", err)
+	}
+
+	deadline := time.Now().Add(journalLockWait)
+	for {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(file) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock offline queue: %w", err)
+		}
+
+		// Take over a lock nobody has touched for a while
+		if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(file)
+			continue
+		}
+		if !wait {
+			return nil, ErrBusy
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("offline queue is locked by another clockify-tracker (remove %s if none is running)", file)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// Load reads the user's queued entries, oldest first
+// A user with nothing queued gets an empty list, not an error
+func Load(userID string) ([]Item, error) {
+	data, err := os.ReadFile(path(userID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+
+	var items []Item
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return nil, fmt.Errorf("offline queue %s line %d is damaged: %w", path(userID), line, err)
+		}
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
+
+// Add appends an entry that failed to send, unless the same entry is already queued
+// Returns how many entries are now pending
+func Add(userID, workspaceID string, input api.TimeEntryInput, reason error) (int, error) {
+	unlock, err := lock(path(userID)+".lock", journalLockStale, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	items, err := Load(userID)
+	if err != nil {
+		return 0, err
+	}
+	entry := newEntry(input)
+	for _, item := range items {
+		if item.WorkspaceID == workspaceID && sameEntry(item.Entry, entry) {
+			return len(items), nil
+		}
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return 0, err
+	}
+	item := Item{
+		ID:          hex.EncodeToString(id),
+		WorkspaceID: workspaceID,
+		Entry:       entry,
+		QueuedAt:    time.Now(),
+		Reason:      reason.Error(),
+	}
+	line, err := json.Marshal(item)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode queued entry: %w", err)
+	}
+
+	// Appending keeps what's already queued safe even if we crash mid-write
+	file := path(userID)
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return 0, fmt.Errorf("failed to save offline queue: %w", err)
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save offline queue: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return 0, fmt.Errorf("failed to save offline queue: %w", err)
+	}
+	return len(items) + 1, nil
+}
+
+// Flush sends the user's queued entries through CreateTimeEntry, oldest first
+// Entries already in Clockify (e.g., the request got through but the response
+// didn't) are dropped instead of created twice. Sending stops at the first
+// temporary failure, since the rest would fail too; an entry Clockify rejects
+// stays queued with the reason, and the first such error is returned
+// Only one process sends at a time - the others get ErrBusy
+func Flush(ctx context.Context, client *api.Client, userID string) (Result, error) {
+	flushLock := path(userID) + ".flush.lock"
+	unlock, err := lock(flushLock, flushLockStale, false)
+	if err != nil {
+		items, _ := Load(userID)
+		return Result{Pending: len(items)}, err
+	}
+	defer unlock()
+
+	items, err := Load(userID)
+	if err != nil || len(items) == 0 {
+		return Result{}, err
+	}
+
+	var result Result
+	var firstErr error
+	done := map[string]bool{}      // IDs sent or found to be duplicates
+	reasons := map[string]string{} // IDs that failed again, with why
+	for _, item := range items {
+		now := time.Now()
+		_ = os.Chtimes(flushLock, now, now) // Still sending, so the lock isn't stale
+		duplicate, err := inClockify(ctx, client, userID, item)
+		if err == nil && !duplicate {
+			err = client.CreateTimeEntry(ctx, item.WorkspaceID, item.Entry.Input())
+		}
+		if err != nil {
+			reasons[item.ID] = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+			if api.IsTemporary(err) || api.IsCanceled(err) {
+				break
+			}
+			continue
+		}
+
+		done[item.ID] = true
+		if duplicate {
+			result.Duplicates++
+		} else {
+			result.Sent++
+		}
+	}
+
+	pending, err := rewrite(userID, done, reasons)
+	if err != nil {
+		return result, err
+	}
+	result.Pending = pending
+	return result, firstErr
+}
+
+// inClockify reports whether an entry like item's already exists in Clockify
+func inClockify(ctx context.Context, client *api.Client, userID string, item Item) (bool, error) {
+	entries, err := client.GetTimeEntries(ctx, item.WorkspaceID, userID, item.Entry.Start, item.Entry.End)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.ProjectID == item.Entry.ProjectID && entry.Description == item.Entry.Description &&
+			entry.TimeInterval.Start.Equal(item.Entry.Start) && entry.TimeInterval.End.Equal(item.Entry.End) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rewrite drops the done items from the journal and records new failure reasons
+// The file is read again first, so entries queued while flushing are kept
+// Returns how many entries are left
+func rewrite(userID string, done map[string]bool, reasons map[string]string) (int, error) {
+	unlock, err := lock(path(userID)+".lock", journalLockStale, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	items, err := Load(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	left := 0
+	for _, item := range items {
+		if done[item.ID] {
+			continue
+		}
+		if reason, ok := reasons[item.ID]; ok {
+			item.Reason = reason
+		}
+		line, err := json.Marshal(item)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode queued entry: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+		left++
+	}
+
+	file := path(userID)
+	if left == 0 {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to save offline queue: %w", err)
+		}
+		return 0, nil
+	}
+	if err := utils.WriteFileAtomic(file, buf.Bytes(), 0o600); err != nil {
+		return 0, fmt.Errorf("failed to save offline queue: %w", err)
+	}
+	return left, nil
+}
+
+// sameEntry reports whether two entries would create the same time entry
+func sameEntry(a, b Entry) bool {
+	return a.ProjectID == b.ProjectID && a.TaskID == b.TaskID && a.Description == b.Description &&
+		a.Start.Equal(b.Start) && a.End.Equal(b.End)
+}
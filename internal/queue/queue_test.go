@@ -0,0 +1,142 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+)
+
+// entryAt is an entry starting at the given hour of a fixed day
+func entryAt(hour int) api.TimeEntryInput {
+	start := time.Date(2024, time.March, 15, hour, 0, 0, 0, time.UTC)
+	return api.TimeEntryInput{ProjectID: "proj-1", Description: fmt.Sprintf("entry %d", hour), Start: start, End: start.Add(time.Hour)}
+}
+
+func TestAddConcurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var wg sync.WaitGroup
+	for hour := range 12 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Add("user-1", "ws-1", entryAt(hour), errors.New("offline")); err != nil {
+				t.Errorf("Add failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	items, err := Load("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 12 {
+		t.Errorf("queued %d entries, want 12", len(items))
+	}
+
+	// The same entry again is skipped
+	pending, err := Add("user-1", "ws-1", entryAt(0), errors.New("offline"))
+	if err != nil || pending != 12 {
+		t.Errorf("Add of a queued entry = %d, %v; want 12", pending, err)
+	}
+}
+
+func TestJournalFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := Add("user-1", "ws-1", entryAt(9), errors.New("offline")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path("user-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"projectId":"proj-1"`, `"description":"entry 9"`, `"start":"2024-03-15T09:00:00Z"`, `"end":"2024-03-15T10:00:00Z"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("journal %s doesn't contain %s", data, field)
+		}
+	}
+
+}
+
+func TestFlushKeepsEntriesAddedMeanwhile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Clockify has nothing yet and accepts every entry; while the first one is
+	// being created, another entry is queued
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			once.Do(func() {
+				if _, err := Add("user-1", "ws-1", entryAt(14), errors.New("offline")); err != nil {
+					t.Errorf("Add during Flush failed: %v", err)
+				}
+			})
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"created"}`)
+			return
+		}
+		fmt.Fprint(w, "[]")
+	}))
+	defer server.Close()
+	client := api.NewClient("test-key", api.WithBaseURL(server.URL), api.WithMaxRetries(0))
+
+	for _, hour := range []int{9, 10} {
+		if _, err := Add("user-1", "ws-1", entryAt(hour), errors.New("offline")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := Flush(context.Background(), client, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 2 || result.Pending != 1 {
+		t.Errorf("Flush = %+v, want 2 sent and 1 pending", result)
+	}
+
+	items, err := Load("user-1")
+	if err != nil || len(items) != 1 || items[0].Entry.Description != "entry 14" {
+		t.Errorf("left in the queue: %+v, %v; want only entry 14", items, err)
+	}
+}
+
+func TestFlushBusy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := Add("user-1", "ws-1", entryAt(9), errors.New("offline")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process is sending - this one leaves the queue alone
+	flushLock := path("user-1") + ".flush.lock"
+	if err := os.WriteFile(flushLock, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Flush(context.Background(), api.NewClient("test-key", api.WithBaseURL("http://127.0.0.1:0")), "user-1")
+	if !errors.Is(err, ErrBusy) || result.Pending != 1 {
+		t.Errorf("Flush = %+v, %v; want ErrBusy with 1 pending", result, err)
+	}
+
+	// A lock left behind by a crashed process is taken over
+	old := time.Now().Add(-2 * flushLockStale)
+	if err := os.Chtimes(flushLock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Flush(context.Background(), api.NewClient("test-key", api.WithBaseURL("http://127.0.0.1:0"), api.WithMaxRetries(0)), "user-1"); errors.Is(err, ErrBusy) {
+		t.Error("Flush didn't take over a stale lock")
+	}
+	if _, err := os.Stat(flushLock); err == nil {
+		t.Errorf("Flush left %s behind", filepath.Base(flushLock))
+	}
+}
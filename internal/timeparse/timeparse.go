@@ -0,0 +1,242 @@
+// Parses and formats the short time strings users type, e.g. "9a - 5:30p" or "1h30m"
+package timeparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsRange reports whether input looks like a clock range rather than a duration
+func IsRange(input string) bool {
+	return strings.ContainsAny(input, "-–")
+}
+
+// ParseInput accepts either a clock range ("9a - 5p") or a duration ("2h")
+// Durations start at anchor, which should fall on date
+func ParseInput(input string, date, anchor time.Time) (time.Time, time.Time, error) {
+	if IsRange(input) {
+		return ParseRange(input, date)
+	}
+
+	d, err := ParseDuration(input)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	// Keep the entry on a single day - ending exactly at midnight is fine
+	start := anchor
+	end := start.Add(d)
+	nextDay := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, date.Location())
+	if end.After(nextDay) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s starting at %s runs past midnight", formatDuration(d), start.Format("3:04 PM"))
+	}
+
+	return start, end, nil
+}
+
+// ParseDuration reads a duration like "2h", "1h30m", "1.5h" or "90m"
+func ParseDuration(input string) (time.Duration, error) {
+	s := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(input)), " ", "")
+	if s == "" {
+		return 0, fmt.Errorf("duration is empty")
+	}
+
+	// time.ParseDuration handles all the forms we want but also accepts things
+	// like "-2h" and "5s", so only allow hours and minutes
+	if strings.Trim(s, "0123456789.hm") != "" || strings.HasPrefix(s, ".") {
+		return 0, fmt.Errorf("%q is not a valid time range or duration (try 9a - 5p, 2h, 1h30m or 90m)", strings.TrimSpace(input))
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid time range or duration (try 9a - 5p, 2h, 1h30m or 90m)", strings.TrimSpace(input))
+	}
+	if d < time.Minute {
+		return 0, fmt.Errorf("duration must be at least one minute")
+	}
+
+	return d.Round(time.Minute), nil
+}
+
+// ParseRange splits a time range like "9a - 5p" into start and end times on the given date
+// The range must be positive - "5p - 9a" and "9a - 9a" are rejected
+func ParseRange(timeRange string, date time.Time) (time.Time, time.Time, error) {
+	// Accept an en dash too, since some keyboards autocorrect to it
+	normalized := strings.ReplaceAll(timeRange, "–", "-")
+	parts := strings.Split(normalized, "-")
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("expected a start and end separated by '-', e.g. '9a - 5p'")
+	}
+
+	start, err := ParseTime(parts[0], date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start time: %w", err)
+	}
+
+	end, err := ParseTime(parts[1], date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end time: %w", err)
+	}
+
+	// Midnight at the end of a range means the end of the day, not the start
+	if end.Hour() == 0 && end.Minute() == 0 {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	if end.Equal(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start and end are both %s", start.Format("3:04 PM"))
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end time %s is before start time %s", end.Format("3:04 PM"), start.Format("3:04 PM"))
+	}
+
+	return start, end, nil
+}
+
+// ParseTime converts a single time string to a full time.Time on the given date
+// Supported forms: 9a, 9:30p, 9:30 pm, 930a, 13:30, 1330, 13, noon, midnight
+func ParseTime(timeStr string, date time.Time) (time.Time, error) {
+	// Normalize the string: lowercase, remove spaces
+	input := strings.TrimSpace(timeStr)
+	s := strings.ReplaceAll(strings.ToLower(input), " ", "")
+	if s == "" {
+		return time.Time{}, fmt.Errorf("time is empty")
+	}
+
+	hour, minute, err := parseClock(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a valid time: %w (try 9a, 9:30p, 13:30 or noon)", input, err)
+	}
+
+	// Combine the date with our parsed time
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location()), nil
+}
+
+// parseClock turns a normalized time string into 24-hour hour and minute values
+func parseClock(s string) (int, int, error) {
+	switch s {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	// Strip an am/pm suffix, remembering which one it was
+	meridiem := ""
+	for _, suffix := range []string{"am", "pm", "a", "p"} {
+		if strings.HasSuffix(s, suffix) {
+			meridiem = suffix[:1]
+			s = strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+
+	hour, minute, err := parseDigits(s)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if minute > 59 {
+		return 0, 0, fmt.Errorf("minutes must be 0-59")
+	}
+
+	// Without am/pm the hour is on a 24-hour clock
+	if meridiem == "" {
+		if hour > 23 {
+			return 0, 0, fmt.Errorf("hour must be 0-23")
+		}
+		return hour, minute, nil
+	}
+
+	// Convert to 24-hour format
+	if hour < 1 || hour > 12 {
+		return 0, 0, fmt.Errorf("hour must be 1-12 with am/pm")
+	}
+	if meridiem == "p" && hour != 12 {
+		hour += 12 // 1pm = 13, 2pm = 14, etc.
+	} else if meridiem == "a" && hour == 12 {
+		hour = 0 // 12am = midnight = 0
+	}
+
+	return hour, minute, nil
+}
+
+// parseDigits reads "9", "09", "9:30" or the compact "930"/"0930" forms
+func parseDigits(s string) (int, int, error) {
+	if s == "" {
+		return 0, 0, fmt.Errorf("missing hour")
+	}
+	if strings.Trim(s, "0123456789:") != "" {
+		return 0, 0, fmt.Errorf("expected digits with an optional am/pm")
+	}
+
+	hourStr, minuteStr := s, "0"
+	if i := strings.Index(s, ":"); i >= 0 {
+		hourStr, minuteStr = s[:i], s[i+1:]
+		if len(minuteStr) != 2 {
+			return 0, 0, fmt.Errorf("minutes must be two digits")
+		}
+	} else if len(s) == 3 || len(s) == 4 {
+		// Compact form - the last two digits are minutes
+		hourStr, minuteStr = s[:len(s)-2], s[len(s)-2:]
+	} else if len(s) > 4 {
+		return 0, 0, fmt.Errorf("too many digits")
+	}
+
+	hour, err := atoi(hourStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unrecognized hour %q", hourStr)
+	}
+	minute, err := atoi(minuteStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unrecognized minutes %q", minuteStr)
+	}
+
+	return hour, minute, nil
+}
+
+// formatDuration renders a duration as "1h30m" or "2h" without the trailing zero units
+func formatDuration(d time.Duration) string {
+	s := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// atoi is strconv.Atoi restricted to plain digits, so "+5" and "-5" are rejected
+func atoi(s string) (int, error) {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// FormatRange is the inverse of ParseRange
+// It renders two times in the short form the user types, e.g. "9a - 5:30p"
+func FormatRange(start, end time.Time) string {
+	return Format(start) + " - " + Format(end)
+}
+
+// Format renders a single time like "9a" or "3:30p"
+func Format(t time.Time) string {
+	suffix := "a"
+	if t.Hour() >= 12 {
+		suffix = "p"
+	}
+
+	// Convert from 24-hour to 12-hour clock
+	hour := t.Hour() % 12
+	if hour == 0 {
+		hour = 12
+	}
+
+	if t.Minute() == 0 {
+		return fmt.Sprintf("%d%s", hour, suffix)
+	}
+	return fmt.Sprintf("%d:%02d%s", hour, t.Minute(), suffix)
+}
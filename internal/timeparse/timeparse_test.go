@@ -0,0 +1,211 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+)
+
+// day is the date every test parses onto
+var day = time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+// at is a time of day on day
+func at(hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		input        string
+		hour, minute int
+	}{
+		{"9a", 9, 0},
+		{"9am", 9, 0},
+		{"9 AM", 9, 0},
+		{"9:30p", 21, 30},
+		{"9:30 pm", 21, 30},
+		{"930a", 9, 30},
+		{"0930", 9, 30},
+		{"13:30", 13, 30},
+		{"1330", 13, 30},
+		{"13", 13, 0},
+		{"0", 0, 0},
+		{"12a", 0, 0},
+		{"12p", 12, 0},
+		{"12:15a", 0, 15},
+		{"noon", 12, 0},
+		{"midnight", 0, 0},
+		{"  5p ", 17, 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.input, day)
+		if err != nil {
+			t.Errorf("ParseTime(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if want := at(tt.hour, tt.minute); !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %s, want %s", tt.input, got.Format("15:04"), want.Format("15:04"))
+		}
+	}
+}
+
+func TestParseTimeRejects(t *testing.T) {
+	for _, input := range []string{
+		"",     // Empty
+		"   ",  // Only spaces
+		"24",   // Hour past 23
+		"13p",  // 24-hour hour with pm
+		"0a",   // No hour zero with am/pm
+		"9:60", // Minutes past 59
+		"960",  // Compact minutes past 59
+		"9:5",  // One-digit minutes
+		"12345",
+		"+9",
+		"-9",
+		"9x",
+		"a",
+		"nine",
+		"9::30",
+	} {
+		if got, err := ParseTime(input, day); err == nil {
+			t.Errorf("ParseTime(%q) = %s, want an error", input, got.Format("15:04"))
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"9a - 5p", at(9, 0), at(17, 0)},
+		{"9a-5:30p", at(9, 0), at(17, 30)},
+		{"9a – 5p", at(9, 0), at(17, 0)}, // En dash
+		{"13:00 - 14:15", at(13, 0), at(14, 15)},
+		{"10p - midnight", at(22, 0), at(24, 0)}, // Midnight at the end is the end of the day
+		{"11p - 12a", at(23, 0), at(24, 0)},
+		{"midnight - 1a", at(0, 0), at(1, 0)},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseRange(tt.input, day)
+		if err != nil {
+			t.Errorf("ParseRange(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("ParseRange(%q) = %s - %s, want %s - %s", tt.input, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestParseRangeRejects(t *testing.T) {
+	for _, input := range []string{
+		"9a",            // No end
+		"9a -",          // Empty end
+		"- 5p",          // Empty start
+		"9a - 5p - 6p",  // Too many parts
+		"5p - 9a",       // Backwards
+		"9a - 9a",       // Empty range
+		"9a - 25",       // Bad end
+		"nope - 5p",     // Bad start
+		"midnight - 0a", // Bad end, even though it looks like midnight
+	} {
+		if start, end, err := ParseRange(input, day); err == nil {
+			t.Errorf("ParseRange(%q) = %s - %s, want an error", input, start, end)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"2h", 2 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+		{"1h 30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"90m", 90 * time.Minute},
+		{"2H", 2 * time.Hour},
+		{"1m", time.Minute},
+		{"0.02h", time.Minute}, // 72s rounds to a minute
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationRejects(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"2",    // No unit
+		"-2h",  // Negative
+		"30s",  // Seconds
+		"0m",   // Too short
+		"0.5m", // Too short
+		".5h",  // Leading dot
+		"2 hours",
+		"h",
+	} {
+		if got, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) = %s, want an error", input, got)
+		}
+	}
+}
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		input      string
+		anchor     time.Time
+		start, end time.Time
+	}{
+		{"9a - 10a", at(14, 0), at(9, 0), at(10, 0)}, // Ranges ignore the anchor
+		{"2h", at(14, 0), at(14, 0), at(16, 0)},
+		{"45m", at(9, 15), at(9, 15), at(10, 0)},
+		{"1h", at(23, 0), at(23, 0), at(24, 0)}, // Ending exactly at midnight is fine
+	}
+	for _, tt := range tests {
+		start, end, err := ParseInput(tt.input, day, tt.anchor)
+		if err != nil {
+			t.Errorf("ParseInput(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("ParseInput(%q) = %s - %s, want %s - %s", tt.input, start, end, tt.start, tt.end)
+		}
+	}
+
+	// A duration can't run into the next day
+	if _, _, err := ParseInput("2h", day, at(23, 0)); err == nil {
+		t.Error("ParseInput(\"2h\") at 11pm should run past midnight")
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	tests := []struct {
+		start, end time.Time
+		want       string
+	}{
+		{at(9, 0), at(17, 30), "9a - 5:30p"},
+		{at(0, 0), at(12, 0), "12a - 12p"},
+		{at(12, 5), at(23, 59), "12:05p - 11:59p"},
+	}
+	for _, tt := range tests {
+		got := FormatRange(tt.start, tt.end)
+		if got != tt.want {
+			t.Errorf("FormatRange(%s, %s) = %q, want %q", tt.start, tt.end, got, tt.want)
+		}
+
+		// What we render has to parse back to the same times
+		start, end, err := ParseRange(got, day)
+		if err != nil || !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("ParseRange(%q) = %s - %s, %v; want %s - %s", got, start, end, err, tt.start, tt.end)
+		}
+	}
+}
@@ -1,82 +1,334 @@
 // internal/ui/commands.go
 // Wraps API calls into Bubble Tea commands
 // Commands are functions that return messages - they bridge the API and UI layers
+// Each command takes a context so Update() can cancel it when the user quits or leaves the screen
 package ui
 
 import (
+	"context"
 	"time"
 
 	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cache"
+	"clockify-time-tracker/internal/history"
+	"clockify-time-tracker/internal/queue"
+	"clockify-time-tracker/internal/utils"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// loadCached returns a command that reads key from the disk cache and wraps the value as a message
+// A miss (or an empty key, when caching is off) sends nothing, leaving it to the fetch that follows
+func loadCached[T any](key string, ttl time.Duration, wrap func(T) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		if key == "" {
+			return nil
+		}
+		value, ok := cache.Load[T](key, ttl)
+		if !ok {
+			return nil
+		}
+		return wrap(value)
+	}
+}
+
+// saveCached stores a fetched value for the next launch
+// A failed write only costs a slower start next time, so it isn't reported
+func saveCached[T any](key string, value T) {
+	if key != "" {
+		_ = cache.Save(key, value)
+	}
+}
+
+// cachedUserInfo returns a command that sends the user info saved by an earlier run
+func cachedUserInfo(key string, ttl time.Duration) tea.Cmd {
+	return loadCached(key, ttl, func(userInfo api.UserInfo) tea.Msg {
+		return userInfoMsg{workspaceID: userInfo.DefaultWorkspace, userID: userInfo.ID}
+	})
+}
+
 // fetchUserInfo returns a command that fetches user information
 // When complete, it sends a userInfoMsg back to Update()
-func fetchUserInfo(apiKey string) tea.Cmd {
+func fetchUserInfo(ctx context.Context, client *api.Client, cacheKey string) tea.Cmd {
 	return func() tea.Msg {
-		// Create API client and fetch user info
-		client := api.NewClient(apiKey)
-		userInfo, err := client.GetUserInfo()
-		
-		// If error, return error message
+		// Fetch user info with the shared API client
+		userInfo, err := client.GetUserInfo(ctx)
+
+		// If error, return a load error - Update() decides whether it's fatal
 		if err != nil {
-			return errMsg(err)
+			return loadErrMsg{err: err, retry: fetchUserInfo(ctx, client, cacheKey)}
 		}
+		saveCached(cacheKey, *userInfo)
 
 		// Success - return user info message with workspace and user IDs
 		return userInfoMsg{
 			workspaceID: userInfo.DefaultWorkspace,
 			userID:      userInfo.ID,
+			checked:     true,
 		}
 	}
 }
 
-// fetchProjects returns a command that fetches all projects
-// When complete, it sends a projectsMsg back to Update()
-func fetchProjects(apiKey, workspaceID string) tea.Cmd {
+// loadProfile returns a command that loads the settings of a profile
+// When complete, it sends a profileMsg back to Update()
+func loadProfile(name string) tea.Cmd {
+	return func() tea.Msg {
+		config, err := utils.LoadConfig(name)
+		return profileMsg{config: config, err: err}
+	}
+}
+
+// fetchWorkspaces returns a command that fetches the user's workspaces
+// When complete, it sends a workspacesMsg back to Update()
+func fetchWorkspaces(ctx context.Context, client *api.Client, cacheKey string) tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewClient(apiKey)
-		projects, err := client.GetProjects(workspaceID)
-		
+		workspaces, err := client.GetWorkspaces(ctx)
+
 		if err != nil {
+			return loadErrMsg{err: err, retry: fetchWorkspaces(ctx, client, cacheKey)}
+		}
+		saveCached(cacheKey, workspaces)
+
+		return workspacesMsg(workspaces)
+	}
+}
+
+// loadHistory returns a command that reads the user's project history from disk
+// When complete, it sends a historyMsg back to Update()
+func loadHistory(userID string) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := history.Load(userID)
+		return historyMsg{projects: projects, err: err}
+	}
+}
+
+// saveHistory returns a command that writes the project history to disk
+// Pass a clone so Update() can keep changing its own copy meanwhile
+func saveHistory(projects history.Projects) tea.Cmd {
+	return func() tea.Msg {
+		if err := projects.Save(); err != nil {
 			return errMsg(err)
 		}
+		return nil
+	}
+}
+
+// fetchProjects returns a command that fetches all projects
+// When complete, it sends a projectsRefreshedMsg back to Update(), which swaps
+// them in for any saved projects without moving the cursor
+func fetchProjects(ctx context.Context, client *api.Client, workspaceID, cacheKey string) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := client.GetProjects(ctx, workspaceID)
+
+		if err != nil {
+			return loadErrMsg{err: err, retry: fetchProjects(ctx, client, workspaceID, cacheKey)}
+		}
+		saveCached(cacheKey, projects)
 
-		// Wrap the projects slice in projectsMsg type
-		// This is crucial - it converts []api.Project to projectsMsg
-		return projectsMsg(projects)
+		// A refresh, unlike the cached projectsMsg, is merged into the list
+		// instead of replacing it, so the highlighted project stays highlighted
+		return projectsRefreshedMsg(projects)
 	}
 }
 
 // fetchTasks returns a command that fetches recent task descriptions
 // When complete, it sends a tasksMsg back to Update()
-func fetchTasks(apiKey, workspaceID, userID string) tea.Cmd {
+func fetchTasks(ctx context.Context, client *api.Client, workspaceID, userID, cacheKey string) tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewClient(apiKey)
-		tasks, err := client.GetTasks(workspaceID, userID)
-		
+		tasks, err := client.GetTasks(ctx, workspaceID, userID)
+
 		if err != nil {
 			return errMsg(err)
 		}
+		saveCached(cacheKey, tasks)
 
 		// Wrap the tasks slice in tasksMsg type
 		return tasksMsg(tasks)
 	}
 }
 
-// createTimeEntry returns a command that creates a time entry
-// When complete, it sends either submitSuccessMsg or errMsg
-func createTimeEntry(apiKey, workspaceID, projectID, description, timeRange string, date time.Time) tea.Cmd {
+// fetchProjectTasks returns a command that fetches the Clockify tasks of one project
+// When complete, it sends a projectTasksMsg back to Update()
+func fetchProjectTasks(ctx context.Context, client *api.Client, workspaceID, projectID string) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := client.GetProjectTasks(ctx, workspaceID, projectID)
+
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return projectTasksMsg{projectID: projectID, tasks: tasks}
+	}
+}
+
+// fetchTags returns a command that fetches the workspace's tags
+// When complete, it sends a tagsMsg back to Update()
+func fetchTags(ctx context.Context, client *api.Client, workspaceID, cacheKey string) tea.Cmd {
+	return func() tea.Msg {
+		tags, err := client.GetTags(ctx, workspaceID)
+
+		if err != nil {
+			return errMsg(err)
+		}
+		saveCached(cacheKey, tags)
+
+		return tagsMsg(tags)
+	}
+}
+
+// createTag returns a command that adds a new tag to the workspace
+// When complete, it sends either tagCreatedMsg or errMsg
+func createTag(ctx context.Context, client *api.Client, workspaceID, name string) tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewClient(apiKey)
-		err := client.CreateTimeEntry(workspaceID, projectID, description, timeRange, date)
-		
+		tag, err := client.CreateTag(ctx, workspaceID, name)
+
 		if err != nil {
 			return errMsg(err)
 		}
 
+		return tagCreatedMsg{tag: *tag}
+	}
+}
+
+// createTimeEntry returns a command that creates a time entry
+// When complete, it sends either submitSuccessMsg or submitErrMsg
+// If Clockify can't be reached, the entry is saved to the offline queue instead
+func createTimeEntry(ctx context.Context, client *api.Client, workspaceID, userID string, entry api.TimeEntryInput) tea.Cmd {
+	return func() tea.Msg {
+		err := client.CreateTimeEntry(ctx, workspaceID, entry)
+
+		if err != nil && api.IsTemporary(err) {
+			pending, queueErr := queue.Add(userID, workspaceID, entry, err)
+			if queueErr == nil {
+				return submitSuccessMsg{queued: true, pending: pending}
+			}
+		}
+		if err != nil {
+			return submitErrMsg{err: err}
+		}
+
 		// Success - return success message
 		return submitSuccessMsg{}
 	}
 }
+
+// flushQueue returns a command that sends entries saved while offline
+// When complete, it sends a queueFlushedMsg back to Update()
+func flushQueue(ctx context.Context, client *api.Client, userID string) tea.Cmd {
+	return func() tea.Msg {
+		result, err := queue.Flush(ctx, client, userID)
+		return queueFlushedMsg{result: result, err: err}
+	}
+}
+
+// fetchTimeEntries returns a command that fetches all entries for a single day
+// When complete, it sends either entriesMsg or entriesErrMsg
+// When complete, it sends an entriesMsg back to Update()
+func fetchTimeEntries(ctx context.Context, client *api.Client, workspaceID, userID string, date time.Time) tea.Cmd {
+	return func() tea.Msg {
+		// Cover the whole day in local time
+		start := utils.StartOfDay(date)
+		end := start.AddDate(0, 0, 1)
+
+		entries, err := client.GetTimeEntries(ctx, workspaceID, userID, start, end)
+
+		if err != nil {
+			return entriesErrMsg{err: err}
+		}
+
+		return entriesMsg(entries)
+	}
+}
+
+// updateTimeEntry returns a command that overwrites an existing time entry
+// When complete, it sends either submitSuccessMsg or submitErrMsg
+func updateTimeEntry(ctx context.Context, client *api.Client, workspaceID, entryID string, entry api.TimeEntryInput) tea.Cmd {
+	return func() tea.Msg {
+		err := client.UpdateTimeEntry(ctx, workspaceID, entryID, entry)
+
+		if err != nil {
+			return submitErrMsg{err: err}
+		}
+
+		return submitSuccessMsg{}
+	}
+}
+
+// deleteTimeEntry returns a command that deletes a time entry
+// When complete, it sends either deleteSuccessMsg or deleteErrMsg
+func deleteTimeEntry(ctx context.Context, client *api.Client, workspaceID, entryID string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.DeleteTimeEntry(ctx, workspaceID, entryID)
+
+		if err != nil {
+			return deleteErrMsg{err: err}
+		}
+
+		return deleteSuccessMsg{}
+	}
+}
+
+// fetchRunningEntry returns a command that checks for an already-running timer
+// When complete, it sends a runningEntryMsg back to Update()
+func fetchRunningEntry(ctx context.Context, client *api.Client, workspaceID, userID string) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := client.GetRunningTimeEntry(ctx, workspaceID, userID)
+
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return runningEntryMsg{entry: entry}
+	}
+}
+
+// startTimer returns a command that starts a live timer right now
+// When complete, it sends either timerStartedMsg or submitErrMsg
+func startTimer(ctx context.Context, client *api.Client, workspaceID string, input api.TimeEntryInput) tea.Cmd {
+	return func() tea.Msg {
+		input.Start = time.Now()
+		entry, err := client.StartTimer(ctx, workspaceID, input)
+
+		if err != nil {
+			return submitErrMsg{err: err}
+		}
+
+		return timerStartedMsg{entry: entry}
+	}
+}
+
+// stopTimer returns a command that stops the running timer right now
+// When complete, it sends either timerStoppedMsg or errMsg
+func stopTimer(ctx context.Context, client *api.Client, workspaceID, userID string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.StopTimer(ctx, workspaceID, userID, time.Now())
+
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return timerStoppedMsg{}
+	}
+}
+
+// tick returns a command that sends a tickMsg after one second
+// Update() keeps re-issuing it while the timer screen is shown
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// fetchWeekEntries returns a command that fetches a full Monday-Sunday week of entries
+// When complete, it sends a weekEntriesMsg back to Update()
+func fetchWeekEntries(ctx context.Context, client *api.Client, workspaceID, userID string, weekStart time.Time) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := client.GetTimeEntries(ctx, workspaceID, userID, weekStart, weekStart.AddDate(0, 0, 7))
+
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return weekEntriesMsg{weekStart: weekStart, entries: entries}
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cache"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadCached(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	workspaces := []api.Workspace{{ID: "ws-1", Name: "Acme"}}
+	if err := cache.Save("workspaces-user-1", workspaces); err != nil {
+		t.Fatal(err)
+	}
+	wrap := func(ws []api.Workspace) tea.Msg { return workspacesMsg(ws) }
+
+	tests := []struct {
+		name string
+		key  string
+		ttl  time.Duration
+		want tea.Msg // nil when nothing should be sent
+	}{
+		{"saved", "workspaces-user-1", time.Hour, workspacesMsg(workspaces)},
+		{"caching off", "workspaces-user-1", 0, nil},
+		{"no key", "", time.Hour, nil},
+		{"never saved", "workspaces-user-2", time.Hour, nil},
+		{"too old", "workspaces-user-1", time.Nanosecond, nil},
+	}
+	for _, tt := range tests {
+		got := loadCached(tt.key, tt.ttl, wrap)()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: loadCached sent %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Lets the [keys] section of config.toml rebind single-key actions
+package ui
+
+import "strings"
+
+// keyMap translates a pressed key into the default key handleKeyPress understands
+// A default key that was rebound to something else does nothing on its own
+type keyMap map[string]string
+
+// newKeyMap builds the translation from the configured key for each action
+func newKeyMap(keys map[string]string, defaults map[string]string) keyMap {
+	km := keyMap{}
+	for action, key := range keys {
+		def := defaults[action]
+		if key == def {
+			continue
+		}
+		if _, taken := km[def]; !taken {
+			km[def] = "" // Free the default key...
+		}
+		km[key] = def // ...and make the new key act like it
+	}
+	return km
+}
+
+// resolve returns the default key for a pressed key
+func (km keyMap) resolve(pressed string) string {
+	if key, ok := km[pressed]; ok {
+		return key
+	}
+	return pressed
+}
+
+// promptsFor lists the prompts that mention each rebindable action's key
+var promptsFor = map[string][]*string{
+	"quit":       {&QuitPrompt},
+	"today":      {&TodayPrompt, &ThisWeekPrompt},
+	"retry":      {&RetryPrompt},
+	"week":       {&WeekPrompt},
+	"edit":       {&EditEntriesPrompt},
+	"timer":      {&StartTimerPrompt, &StopTimerPrompt},
+	"view_timer": {&ViewTimerPrompt},
+	"delete":     {&DeletePrompt},
+	"favorite":   {&FavoritePrompt},
+	"billable":   {&BillablePrompt},
+	"search":     {&SearchPrompt},
+}
+
+// bindPrompts rewrites the prompts so they show the configured keys
+func bindPrompts(keys map[string]string, defaults map[string]string) {
+	for action, key := range keys {
+		for _, prompt := range promptsFor[action] {
+			*prompt = strings.Replace(*prompt, "["+defaults[action], "["+key, 1)
+		}
+	}
+}
@@ -2,9 +2,12 @@
 package ui
 
 import (
+	"context"
 	"time"
 
 	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cache"
+	"clockify-time-tracker/internal/history"
 	"clockify-time-tracker/internal/utils"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -18,29 +21,86 @@ type model struct {
 	step int
 
 	// Data from API
-	projects []api.Project // List of available projects
-	tasks    []string      // Recent task descriptions for suggestions
+	workspaces      []api.Workspace         // Workspaces the user belongs to
+	projects        []api.Project           // List of available projects
+	tasks           []api.TaskUsage         // How recent task descriptions were used, for suggestions
+	entries         []api.TimeEntryResponse // Entries logged on the selected date
+	weekEntries     []api.TimeEntryResponse // Entries logged in the week being viewed
+	tags            []api.Tag               // Workspace tags that can be attached to entries
+	projectHistory  history.Projects        // Favorite and recently used projects, saved locally
+	historyLoaded   bool                    // Whether projectHistory was read from disk yet
+	projectTasks    []api.ProjectTask       // Clockify tasks of the selected project
+	projectTasksFor string                  // ID of the project projectTasks belongs to
 
 	// Navigation state
 	cursor   int // Current position in lists (for arrow key navigation)
 	selected int // Index of selected item (not currently used but kept for future)
 
 	// User inputs
-	date          time.Time           // Selected date for time entry
-	timeRange     textinput.Model     // Text input for time range (e.g., "9a - 5p")
-	taskName      textinput.Model     // Text input for task description
-	projectSearch textinput.Model     // Text input for project search
-	selectedProj  api.Project         // The project user selected
+	date            time.Time       // Selected date for time entry
+	weekStart       time.Time       // Monday of the week shown in the timesheet
+	timeRange       textinput.Model // Text input for time range (e.g., "9a - 5p")
+	taskName        textinput.Model // Text input for task description
+	suggestion      int             // Highlighted row in the task suggestion dropdown
+	projectSearch   textinput.Model // Text input for project search
+	tagSearch       textinput.Model // Text input for tag search, also used to name a new tag
+	selectedTags    []string        // IDs of the tags chosen for the entry
+	billable        bool            // Whether the entry is billable, defaulting to the project's setting
+	selectedProj    api.Project     // The project user selected
+	selectedTask    string          // ID of the chosen Clockify task within the project ("" for none)
+	editingEntry    string          // ID of the entry being edited ("" when creating)
+	startTime       time.Time       // Start of the entry, resolved from the time input
+	endTime         time.Time       // End of the entry, resolved from the time input
+	timerMode       bool            // Whether we're starting/viewing a live timer instead of logging a range
+	returnToConfirm bool            // Whether Enter should jump back to the confirm screen (editing one field)
 
 	// API credentials and IDs
-	apiKey      string // Clockify API key
-	workspaceID string // User's workspace ID (fetched from API)
-	userID      string // User's ID (fetched from API)
+	client      *api.Client // Shared Clockify API client, built from the config
+	profiles    []string    // Profiles offered by the picker; "" stands for the default .env key
+	profileName string      // Name of the active profile, shown in the header
+	userChecked bool        // Whether the API key was confirmed by Clockify (not just read from the cache)
+	failedLoads []tea.Cmd   // Startup fetches behind loadErr, repeated by [r]
+
+	// Disk cache of Clockify data, so lists show up before the API answers
+	cacheTTL       time.Duration // How old saved data may be (0 turns the cache off)
+	accountKey     string        // Cache key for the user info of the current API key
+	projectsCached bool          // Whether the project list is the saved copy, still being refreshed
+
+	// Request cancellation
+	ctx              context.Context    // Cancelled when the program quits
+	cancel           context.CancelFunc // Cancels ctx
+	screenCtx        context.Context    // Child of ctx for the current screen's fetches
+	screenCancel     context.CancelFunc // Cancels screenCtx when the user navigates away
+	dayStart         string             // Default start of the working day for durations (e.g., "9a")
+	location         *time.Location     // Time zone dates are picked and shown in
+	defaultProject   string             // Configured project name or ID to highlight when nothing was picked yet
+	keys             keyMap             // Keys rebound in config.toml
+	workspaceKey     string             // Configured workspace ID or name ("" to ask when there are several)
+	defaultWorkspace string             // The user's default workspace ID, highlighted in the picker
+	workspaceID      string             // ID of the workspace entries are logged to (chosen after login)
+	workspaceName    string             // Name of that workspace, shown in the header
+	userID           string             // User's ID (fetched from API)
+
+	// Live timer state
+	runningEntry *api.TimeEntryResponse // The running timer, if any
+	now          time.Time              // Last tick time, used to compute elapsed time
+	ticking      bool                   // Whether a tick loop is already active
 
 	// Status flags
-	err        error // Any error that occurred
-	submitting bool  // Whether we're currently submitting (not used yet)
-	success    bool  // Whether submission was successful
+	err                 error // Fatal error - the app shows it and exits
+	loadErr             error // Loading user info or projects failed; [r] retries
+	submitErr           error // The last submit failed; shown on the confirm screen with [r] to retry
+	notice              error // A background operation failed; cleared on the next key press
+	timerStopped        bool  // Whether to say the timer was stopped; cleared on the next key press
+	submitting          bool  // Whether we're currently submitting
+	deleting            bool  // Whether an entry is being deleted
+	success             bool  // Whether submission was successful
+	queued              bool  // Whether the last entry went to the offline queue instead of Clockify
+	pending             int   // How many entries are waiting in the offline queue
+	loadingEntries      bool  // Whether the day's entries are being fetched
+	entriesErr          error // Fetching the day's entries failed, so durations can't be placed
+	loadingWeek         bool  // Whether the week's entries are being fetched
+	loadingProjectTasks bool  // Whether the selected project's tasks are being fetched
 }
 
 // New creates and initializes a new model with the provided configuration
@@ -48,14 +108,15 @@ type model struct {
 func New(config *utils.Config) model {
 	// Create and configure the time range text input
 	ti := textinput.New()
-	ti.Placeholder = "9a - 5p"                // Show example format
-	ti.CharLimit = 20                         // Reasonable limit for time strings
+	ti.Placeholder = "9a - 5p or 2h" // Show example formats
+	ti.CharLimit = 20                // Reasonable limit for time strings
 	ti.Width = 30
+	ti.SetValue(config.TimeRange) // Configured default, if any
 
 	// Create and configure the task name text input
 	taskInput := textinput.New()
 	taskInput.Placeholder = "Enter task description"
-	taskInput.CharLimit = 200                 // Clockify's description limit
+	taskInput.CharLimit = 200 // Clockify's description limit
 	taskInput.Width = 50
 
 	// Create and configure the project search input
@@ -63,15 +124,61 @@ func New(config *utils.Config) model {
 	searchInput.Placeholder = "Search projects..."
 	searchInput.Width = 50
 
+	// Create and configure the tag search input
+	tagInput := textinput.New()
+	tagInput.Placeholder = "Search or add tags..."
+	tagInput.CharLimit = 100
+	tagInput.Width = 50
+
+	// Preferences from config.toml
+	applyTheme(config.Theme)
+	bindPrompts(config.Keys, utils.DefaultKeys)
+	location := config.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	// Every request is tied to this context so quitting stops them all
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Without a --profile or CLOCKIFY_PROFILE, ask which account to use before logging in
+	step := stepDateSelect
+	var profiles []string
+	if config.Profile == "" && len(config.Profiles) > 0 {
+		step = stepProfileSelect
+		if config.APIKey != "" {
+			profiles = append(profiles, "")
+		}
+		profiles = append(profiles, config.Profiles...)
+	}
+
+	// Saved user info belongs to one API key
+	accountKey := ""
+	if config.CacheTTL > 0 {
+		accountKey = cache.AccountKey(config.APIKey, config.BaseURL)
+	}
+
 	// Return a new model with initial state
 	return model{
-		step:          stepDateSelect,            // Start at date selection
-		date:          time.Now(),                // Default to today
-		timeRange:     ti,
-		taskName:      taskInput,
-		projectSearch: searchInput,
-		cursor:        0,                         // Start at first item in lists
-		apiKey:        config.APIKey,             // Store API key from config
+		ctx:            ctx,
+		cancel:         cancel,
+		step:           step,                    // Start at date selection, or the profile picker
+		date:           time.Now().In(location), // Default to today
+		timeRange:      ti,
+		taskName:       taskInput,
+		projectSearch:  searchInput,
+		tagSearch:      tagInput,
+		cursor:         0, // Start at first item in lists
+		client:         api.NewClient(config.APIKey, config.ClientOptions()...),
+		profiles:       profiles,
+		profileName:    config.Profile,
+		cacheTTL:       config.CacheTTL,
+		accountKey:     accountKey,
+		dayStart:       config.DayStart,
+		location:       location,
+		defaultProject: config.Project,
+		keys:           newKeyMap(config.Keys, utils.DefaultKeys),
+		workspaceKey:   config.Workspace,
 	}
 }
 
@@ -79,6 +186,12 @@ func New(config *utils.Config) model {
 // It returns a command that will fetch the user's info from Clockify
 // This is part of the Bubble Tea architecture - Init returns initial commands to run
 func (m model) Init() tea.Cmd {
+	// The profile picker decides which account to log in with first
+	if m.step == stepProfileSelect {
+		return nil
+	}
+
 	// Fetch user info (workspace ID and user ID) as our first action
-	return fetchUserInfo(m.apiKey)
+	// A copy saved by the last run is used while it loads
+	return m.loadUser()
 }
@@ -0,0 +1,396 @@
+// First-run setup wizard, shown instead of an error when there's no API key yet
+// It asks for the key, checks it against Clockify, and lets the user pick a
+// default workspace and project. main saves the result once the wizard exits,
+// so a passphrase for the credential store can be asked for on the plain terminal
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/fuzzy"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Steps of the setup wizard
+const (
+	setupStepKey       = iota // 0 - Enter and check the API key
+	setupStepWorkspace        // 1 - Pick the default workspace (only if there are several)
+	setupStepProject          // 2 - Pick the default project, or none
+)
+
+// Setup is what the wizard collected
+type Setup struct {
+	APIKey    string
+	Workspace api.Workspace
+	Project   api.Project // Zero when the user didn't pick a default project
+}
+
+// Messages the setup wizard receives
+type setupUserMsg struct { // The API key was checked against /user
+	apiKey   string
+	userInfo *api.UserInfo
+	err      error
+}
+type setupWorkspacesMsg struct { // The user's workspaces were fetched
+	workspaces []api.Workspace
+	err        error
+}
+type setupProjectsMsg struct { // A workspace's projects were fetched
+	projects []api.Project
+	err      error
+}
+
+// setupModel is the state of the setup wizard
+type setupModel struct {
+	step     int
+	cursor   int
+	keyInput textinput.Model // Masked input for the API key
+	search   textinput.Model // Filters the project list
+
+	ctx    context.Context    // Cancelled when the wizard quits
+	cancel context.CancelFunc // Cancels ctx
+	client *api.Client        // Built once the key is entered
+	opts   []api.Option       // Client options, e.g. a custom base URL
+
+	apiKey     string // The key Clockify accepted
+	userInfo   *api.UserInfo
+	workspaces []api.Workspace
+	projects   []api.Project
+	workspace  api.Workspace // The chosen workspace
+	project    api.Project   // The chosen project (zero for none)
+
+	loading bool  // Whether we're waiting for Clockify
+	err     error // The last problem, shown above the current step
+	done    bool  // Whether the user finished every step
+}
+
+// RunSetup runs the setup wizard until the user finishes or quits
+// Returns nil without an error when the user quit before finishing
+func RunSetup(opts ...api.Option) (*Setup, error) {
+	final, err := tea.NewProgram(newSetup(opts)).Run()
+	if err != nil {
+		return nil, err
+	}
+	m := final.(setupModel)
+	if !m.done {
+		return nil, nil
+	}
+	return &Setup{APIKey: m.apiKey, Workspace: m.workspace, Project: m.project}, nil
+}
+
+// newSetup creates the wizard at its first step
+func newSetup(opts []api.Option) setupModel {
+	keyInput := textinput.New()
+	keyInput.Placeholder = "Paste your API key"
+	keyInput.EchoMode = textinput.EchoPassword // Keep the key off the screen
+	keyInput.Width = 50
+	keyInput.Focus()
+
+	search := textinput.New()
+	search.Placeholder = "Type to filter projects..."
+	search.Width = 50
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return setupModel{
+		step:     setupStepKey,
+		keyInput: keyInput,
+		search:   search,
+		ctx:      ctx,
+		cancel:   cancel,
+		opts:     opts,
+	}
+}
+
+// Init starts the cursor blinking in the key input
+func (m setupModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// checkKey returns a command that checks an API key by fetching the user it belongs to
+func checkKey(ctx context.Context, client *api.Client, apiKey string) tea.Cmd {
+	return func() tea.Msg {
+		userInfo, err := client.GetUserInfo(ctx)
+		return setupUserMsg{apiKey: apiKey, userInfo: userInfo, err: err}
+	}
+}
+
+// setupWorkspaces returns a command that fetches the user's workspaces
+func setupWorkspaces(ctx context.Context, client *api.Client) tea.Cmd {
+	return func() tea.Msg {
+		workspaces, err := client.GetWorkspaces(ctx)
+		return setupWorkspacesMsg{workspaces: workspaces, err: err}
+	}
+}
+
+// setupProjects returns a command that fetches a workspace's projects
+func setupProjects(ctx context.Context, client *api.Client, workspaceID string) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := client.GetProjects(ctx, workspaceID)
+		return setupProjectsMsg{projects: projects, err: err}
+	}
+}
+
+// Update handles key presses and Clockify responses
+func (m setupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	// The key was checked - a rejected key is asked for again
+	case setupUserMsg:
+		m.loading = false
+		if msg.err != nil {
+			if api.IsAuth(msg.err) {
+				m.err = fmt.Errorf("Clockify rejected this API key - check it and try again")
+			} else if !api.IsCanceled(msg.err) {
+				m.err = fmt.Errorf("couldn't check the API key: %w", msg.err)
+			}
+			return m, nil
+		}
+		m.apiKey = msg.apiKey
+		m.userInfo = msg.userInfo
+		m.loading = true
+		return m, setupWorkspaces(m.ctx, m.client)
+
+	// Workspaces were fetched - a single one is picked without asking
+	case setupWorkspacesMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = fmt.Errorf("couldn't load your workspaces: %w", msg.err)
+			return m, nil
+		}
+		m.workspaces = msg.workspaces
+		if len(m.workspaces) == 1 {
+			return m.selectWorkspace(m.workspaces[0])
+		}
+		m.step = setupStepWorkspace
+		m.cursor = 0
+		for i, ws := range m.workspaces {
+			if ws.ID == m.userInfo.DefaultWorkspace {
+				m.cursor = i
+			}
+		}
+		return m, nil
+
+	// Projects were fetched - the list is filtered as the user types
+	case setupProjectsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = fmt.Errorf("couldn't load projects: %w", msg.err)
+			return m, nil
+		}
+		m.projects = msg.projects
+		return m, nil
+	}
+
+	// Anything else (e.g., the cursor blinking) belongs to the focused input
+	var cmd tea.Cmd
+	switch m.step {
+	case setupStepKey:
+		m.keyInput, cmd = m.keyInput.Update(msg)
+	case setupStepProject:
+		m.search, cmd = m.search.Update(msg)
+	}
+	return m, cmd
+}
+
+// handleKey processes key presses for the current step
+func (m setupModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.err = nil
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancel()
+		return m, tea.Quit
+
+	// Esc goes back a step, and leaves the wizard from the first one
+	case "esc":
+		switch m.step {
+		case setupStepKey:
+			m.cancel()
+			return m, tea.Quit
+		case setupStepWorkspace:
+			m.step = setupStepKey
+			m.keyInput.Focus()
+			return m, textinput.Blink
+		case setupStepProject:
+			m.search.Blur()
+			m.search.SetValue("")
+			if len(m.workspaces) > 1 {
+				m.step = setupStepWorkspace
+			} else {
+				m.step = setupStepKey
+				m.keyInput.Focus()
+			}
+			m.cursor = 0
+			return m, nil
+		}
+
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.step == setupStepWorkspace && m.cursor < len(m.workspaces)-1 {
+			m.cursor++
+		}
+		if m.step == setupStepProject && m.cursor < len(m.setupChoices())-1 {
+			m.cursor++
+		}
+		return m, nil
+
+	case "enter":
+		return m.handleEnter()
+	}
+
+	// Everything else is typing
+	var cmd tea.Cmd
+	switch m.step {
+	case setupStepKey:
+		m.keyInput, cmd = m.keyInput.Update(msg)
+	case setupStepProject:
+		m.search, cmd = m.search.Update(msg)
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+// handleEnter confirms the current step
+func (m setupModel) handleEnter() (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	switch m.step {
+	case setupStepKey:
+		apiKey := m.keyInput.Value()
+		if apiKey == "" {
+			return m, nil
+		}
+		m.client = api.NewClient(apiKey, m.opts...)
+		m.loading = true
+		return m, checkKey(m.ctx, m.client, apiKey)
+
+	case setupStepWorkspace:
+		if m.cursor < len(m.workspaces) {
+			return m.selectWorkspace(m.workspaces[m.cursor])
+		}
+
+	case setupStepProject:
+		choices := m.setupChoices()
+		if m.cursor < len(choices) {
+			m.project = choices[m.cursor]
+			m.done = true
+			m.cancel()
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// selectWorkspace remembers the workspace and loads its projects
+func (m setupModel) selectWorkspace(ws api.Workspace) (tea.Model, tea.Cmd) {
+	m.workspace = ws
+	m.step = setupStepProject
+	m.cursor = 0
+	m.projects = nil
+	m.loading = true
+	m.search.Focus()
+	return m, tea.Batch(setupProjects(m.ctx, m.client, ws.ID), textinput.Blink)
+}
+
+// setupChoices lists the projects matching the search, best match first
+// Without a search the first row is "no default project"
+func (m setupModel) setupChoices() []api.Project {
+	query := m.search.Value()
+	if query == "" {
+		return append([]api.Project{{}}, m.projects...)
+	}
+
+	type scored struct {
+		project api.Project
+		score   int
+	}
+	var matches []scored
+	for _, proj := range m.projects {
+		if result, ok := fuzzy.Match(query, proj.Name); ok {
+			matches = append(matches, scored{proj, result.Score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	choices := make([]api.Project, len(matches))
+	for i, match := range matches {
+		choices[i] = match.project
+	}
+	return choices
+}
+
+// View renders the current step of the wizard
+func (m setupModel) View() string {
+	s := titleStyle.Render("⏱️  Clockify Time Tracker · Setup") + "\n\n"
+	if m.err != nil {
+		s += errorStyle.Render("⚠ "+m.err.Error()) + "\n\n"
+	}
+
+	switch m.step {
+	case setupStepKey:
+		s += "Welcome! Let's connect to your Clockify account.\n\n"
+		s += "Create an API key at https://clockify.me/user/settings and paste it here:\n\n"
+		s += m.keyInput.View() + "\n\n"
+		if m.loading {
+			s += "  Checking your key...\n\n"
+		}
+		s += fmt.Sprintf("  %s  [Esc] Quit", ContinuePrompt)
+
+	case setupStepWorkspace:
+		s += fmt.Sprintf("Hi %s! Which workspace should entries go to by default?\n\n", m.userInfo.Name)
+		start, end := visibleRange(m.cursor, len(m.workspaces))
+		for i := start; i < end; i++ {
+			name := m.workspaces[i].Name
+			if m.workspaces[i].ID == m.userInfo.DefaultWorkspace {
+				name += " (default)"
+			}
+			s += renderSetupRow(name, i == m.cursor)
+		}
+		s += fmt.Sprintf("\n  %s %s %s", ArrowPrompt, EnterPrompt, BackPrompt)
+
+	case setupStepProject:
+		s += fmt.Sprintf("Which project in %s should be highlighted when you log time?\n\n", m.workspace.Name)
+		s += m.search.View() + "\n\n"
+		if m.loading {
+			s += "  Loading projects...\n"
+			break
+		}
+		choices := m.setupChoices()
+		if len(choices) == 0 {
+			s += "  No projects match\n"
+		}
+		start, end := visibleRange(m.cursor, len(choices))
+		for i := start; i < end; i++ {
+			name := choices[i].Name
+			if choices[i].ID == "" {
+				name = "(no default project)"
+			}
+			s += renderSetupRow(name, i == m.cursor)
+		}
+		s += fmt.Sprintf("\n  [↑/↓] Navigate %s %s", EnterPrompt, BackPrompt)
+	}
+
+	return s
+}
+
+// renderSetupRow renders one row of a list, marking the cursor
+func renderSetupRow(name string, selected bool) string {
+	if selected {
+		return selectedStyle.Render("❯ "+name) + "\n"
+	}
+	return "  " + name + "\n"
+}
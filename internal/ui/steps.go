@@ -4,10 +4,19 @@ package ui
 // These constants represent each screen in our UI flow
 // Using constants (instead of magic numbers) makes the code more readable
 const (
-	stepDateSelect    = iota // 0 - Select which date to log time for
-	stepProjectSelect        // 1 - Select which project
-	stepTimeInput            // 2 - Enter time range (e.g., "9a - 5p")
-	stepTaskInput            // 3 - Enter task description
-	stepConfirm              // 4 - Review and confirm the entry
-	stepComplete             // 5 - Show success message
+	stepDateSelect        = iota // 0 - Select which date to log time for
+	stepProjectSelect            // 1 - Select which project
+	stepTimeInput                // 2 - Enter time range (e.g., "9a - 5p")
+	stepTaskInput                // 3 - Enter task description
+	stepConfirm                  // 4 - Review and confirm the entry
+	stepComplete                 // 5 - Show success message
+	stepEntryList                // 6 - List the selected day's entries to edit or delete
+	stepDeleteConfirm            // 7 - Confirm deleting an entry
+	stepTimer                    // 8 - Show a running timer with elapsed time
+	stepRunningFound             // 9 - Ask what to do about a timer found at startup
+	stepWeekView                 // 10 - Weekly timesheet of logged hours
+	stepTagSelect                // 11 - Pick tags for the entry
+	stepProjectTaskSelect        // 12 - Pick one of the project's Clockify tasks (only if it has any)
+	stepWorkspaceSelect          // 13 - Pick a workspace when the user belongs to several
+	stepProfileSelect            // 14 - Pick a profile before logging in (only if none was given)
 )
@@ -1,11 +1,34 @@
 package ui
 
-const (
+// Prompts are variables so bindPrompts can show keys rebound in config.toml
+var (
+	AcceptPrompt        = "[tab] Accept suggestion"
 	ArrowPrompt         = "[↑/↓ | j/k] Navigate"
+	BackPrompt          = "[Esc | shift+tab] Back"
+	BillablePrompt      = "[b] Toggle billable"
 	ClearPrompt         = "[Esc] Clear"
+	DeletePrompt        = "[d] Delete"
+	EditEntriesPrompt   = "[e] Edit entries"
+	ContinuePrompt      = "[Enter] Continue"
+	CreateTagPrompt     = "[Enter] Create tag"
+	EditFieldPrompt     = "[1-5] Change field"
+	FavoritePrompt      = "[f] Star favorite"
 	EnterPrompt         = "[Enter] Select"
 	QuitPrompt          = "[q | ctrl+c] Quit"
+	LeaveRunningPrompt  = "[Esc] Leave running"
+	LogAnotherPrompt    = "[a | Enter] Log another"
+	RetryPrompt         = "[r] Retry"
+	SameProjectPrompt   = "[n] Next entry, same project"
 	SearchPrompt        = "[/] Search"
+	SuggestionPrompt    = "[↑/↓] Choose suggestion"
+	StartTimerPrompt    = "[s] Start timer"
+	StopTimerPrompt     = "[s] Stop timer"
 	TodayPrompt         = "[t] Revert to Today"
+	ToggleTagPrompt     = "[space] Toggle tag"
+	YesNoPrompt         = "[y/n] Confirm"
 	VerticalArrowPrompt = "[←/→ | h/l] Change day"
+	ViewTimerPrompt     = "[v] View timer"
+	WeekArrowPrompt     = "[←/→ | h/l] Change week"
+	WeekPrompt          = "[w] Week view"
+	ThisWeekPrompt      = "[t] Revert to this week"
 )
@@ -18,6 +18,11 @@ var (
 			Foreground(lipgloss.Color("170")). // Purple color
 			Bold(true)
 
+	// matchStyle marks the characters that matched a search
+	matchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("212")). // Light pink color
+			Underline(true)
+
 	// errorStyle is used for error messages
 	errorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196")). // Red color
@@ -28,3 +33,21 @@ var (
 			Foreground(lipgloss.Color("42")). // Green color
 			Bold(true)
 )
+
+// applyTheme recolours the styles above for the theme set in config.toml
+// "dark" keeps the colours above, "light" uses darker shades that stay readable
+// on a light background, and "none" drops colour for terminals that render it badly
+func applyTheme(theme string) {
+	switch theme {
+	case "light":
+		titleStyle = titleStyle.Foreground(lipgloss.Color("125"))      // Dark magenta
+		selectedStyle = selectedStyle.Foreground(lipgloss.Color("91")) // Dark purple
+		matchStyle = matchStyle.Foreground(lipgloss.Color("161"))      // Deep pink
+		errorStyle = errorStyle.Foreground(lipgloss.Color("160"))      // Dark red
+		successStyle = successStyle.Foreground(lipgloss.Color("28"))   // Dark green
+	case "none":
+		for _, style := range []*lipgloss.Style{&titleStyle, &selectedStyle, &matchStyle, &errorStyle, &successStyle} {
+			*style = style.UnsetForeground()
+		}
+	}
+}
@@ -3,40 +3,121 @@
 package ui
 
 import (
-	"clockify-time-tracker/internal/api"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cache"
+	"clockify-time-tracker/internal/history"
+	"clockify-time-tracker/internal/queue"
+	"clockify-time-tracker/internal/timeparse"
+	"clockify-time-tracker/internal/utils"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Message types that can be sent to Update()
 // These are custom types that wrap the actual data
-type projectsMsg []api.Project // List of projects from API
-type tasksMsg []string         // List of task descriptions
-type userInfoMsg struct {      // User info from API
+type profileMsg struct { // A profile's settings were loaded
+	config *utils.Config
+	err    error
+}
+type workspacesMsg []api.Workspace // Workspaces the user belongs to
+type historyMsg struct {           // The user's project history was read from disk
+	projects history.Projects // Empty but usable even when err is set
+	err      error
+}
+type projectsMsg []api.Project          // Projects saved on disk by an earlier run
+type projectsRefreshedMsg []api.Project // Current projects from the API
+type tasksMsg []api.TaskUsage           // How recent task descriptions were used
+type tagsMsg []api.Tag                  // Workspace tags
+type projectTasksMsg struct {           // Clockify tasks of a project
+	projectID string
+	tasks     []api.ProjectTask
+}
+type tagCreatedMsg struct { // A new tag was added to the workspace
+	tag api.Tag
+}
+type entriesMsg []api.TimeEntryResponse // Entries for the selected date
+type entriesErrMsg struct {             // Fetching the selected date's entries failed
+	err error
+}
+type userInfoMsg struct { // User info from API
 	workspaceID string
 	userID      string
+	checked     bool // false when it came from the disk cache, so the API key isn't verified yet
+}
+type errMsg error        // Error that occurred - shown as a notice, never fatal on its own
+type loadErrMsg struct { // Loading user info or projects failed
+	err   error
+	retry tea.Cmd // The same fetch again
+}
+type submitErrMsg struct { // Creating, updating or starting an entry failed
+	err error
+}
+type submitSuccessMsg struct { // The entry was saved
+	queued  bool // Clockify couldn't be reached, so it's in the offline queue
+	pending int  // How many entries are queued, when queued is true
+}
+type queueFlushedMsg struct { // Entries saved while offline were sent
+	result queue.Result
+	err    error
+}
+type deleteSuccessMsg struct{} // An entry was deleted
+type deleteErrMsg struct {     // Deleting an entry failed
+	err error
+}
+type runningEntryMsg struct { // Result of checking for a running timer
+	entry *api.TimeEntryResponse // nil when no timer is running
+}
+type timerStartedMsg struct { // A live timer was started
+	entry *api.TimeEntryResponse
+}
+type timerStoppedMsg struct{} // The running timer was stopped
+type tickMsg time.Time        // One second passed on the timer screen
+type weekEntriesMsg struct {  // Entries for a week of the timesheet
+	weekStart time.Time
+	entries   []api.TimeEntryResponse
 }
-type errMsg error              // Error that occurred
-type submitSuccessMsg struct{} // Empty struct signals success
 
 // Update is called whenever a message is received
 // It's the only place where we modify the model
 // Returns the updated model and any commands to run
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Any key press dismisses the last notice
+	if _, isKey := msg.(tea.KeyMsg); isKey {
+		m.notice = nil
+		m.timerStopped = false
+	}
+
 	// Handle text input FIRST before checking message types
-	// This ensures text inputs get all key events
-	if m.step == stepTimeInput || m.step == stepTaskInput || (m.step == stepProjectSelect && m.projectSearch.Focused()) {
+	// This ensures text inputs get all key events, while API results still reach the switch below
+	if _, isKey := msg.(tea.KeyMsg); isKey && (m.step == stepTimeInput || m.step == stepTaskInput || (m.step == stepProjectSelect && m.projectSearch.Focused()) || (m.step == stepTagSelect && m.tagSearch.Focused())) {
+		// The suggestion dropdown under the task input takes the arrow keys and tab
+		if m.step == stepTaskInput {
+			if updated, handled := m.handleSuggestionKey(msg.(tea.KeyMsg)); handled {
+				return updated, nil
+			}
+		}
+
 		var cmd tea.Cmd
 		if m.step == stepTimeInput {
 			m.timeRange, cmd = m.timeRange.Update(msg)
 		} else if m.step == stepTaskInput {
 			m.taskName, cmd = m.taskName.Update(msg)
+			// Typing changes the suggestions, so start again from the best one
+			m.suggestion = 0
 		} else if m.step == stepProjectSelect && m.projectSearch.Focused() {
 			m.projectSearch, cmd = m.projectSearch.Update(msg)
 			// Reset cursor when search changes
 			m.cursor = 0
+		} else if m.step == stepTagSelect && m.tagSearch.Focused() {
+			m.tagSearch, cmd = m.tagSearch.Update(msg)
+			m.cursor = 0
 		}
 
 		// Still check for special keys like Enter and quit keys
@@ -47,11 +128,22 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				return m.handleEnter()
 			case "esc":
+				// In the search box Esc clears the search; everywhere else it goes back
 				if m.step == stepProjectSelect && m.projectSearch.Focused() {
 					m.projectSearch.Blur()
 					m.projectSearch.SetValue("")
 					m.cursor = 0
+					return m, cmd
 				}
+				if m.step == stepTagSelect && m.tagSearch.Focused() {
+					m.tagSearch.Blur()
+					m.tagSearch.SetValue("")
+					m.cursor = 0
+					return m, cmd
+				}
+				return m.goBack()
+			case "shift+tab":
+				return m.goBack()
 			}
 		}
 
@@ -65,63 +157,431 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
-	// User info was fetched successfully
+	// A profile was picked - log in with its key and workspace
+	case profileMsg:
+		if msg.err != nil {
+			m.notice = msg.err
+			return m, nil
+		}
+		return m.useProfile(msg.config.Profile, api.NewClient(msg.config.APIKey, msg.config.ClientOptions()...), msg.config.Workspace, m.accountCacheKey(msg.config))
+
+	// User info was fetched successfully - find out which workspace to use next
+	// Saved user info lets everything else start right away; the fresh copy that
+	// follows only confirms the key unless it's a different user
 	case userInfoMsg:
-		m.workspaceID = msg.workspaceID
+		m.userChecked = m.userChecked || msg.checked
+		m.defaultWorkspace = msg.workspaceID
+		if msg.userID == m.userID {
+			return m, nil
+		}
 		m.userID = msg.userID
-		// Now fetch projects and tasks in parallel using tea.Batch
+		key := m.cacheKey("workspaces", m.userID)
 		return m, tea.Batch(
-			fetchProjects(m.apiKey, m.workspaceID),
-			fetchTasks(m.apiKey, m.workspaceID, m.userID),
+			tea.Sequence(
+				loadCached(key, m.cacheTTL, func(ws []api.Workspace) tea.Msg { return workspacesMsg(ws) }),
+				fetchWorkspaces(m.ctx, m.client, key),
+			),
+			loadHistory(m.userID),
+			flushQueue(m.ctx, m.client, m.userID),
 		)
 
+	// The offline queue was sent - entries Clockify rejected stay queued and are reported
+	case queueFlushedMsg:
+		m.pending = msg.result.Pending
+		// Another process sending the queue reports its own problems
+		if msg.err != nil && !api.IsTemporary(msg.err) && !api.IsCanceled(msg.err) && !errors.Is(msg.err, queue.ErrBusy) {
+			m.notice = fmt.Errorf("an entry saved offline couldn't be sent: %w (run `clockify-tracker sync` for details)", msg.err)
+		}
+		return m, nil
+
+	// Project history was read - a damaged file is reported, then started afresh
+	case historyMsg:
+		m.projectHistory = msg.projects
+		m.historyLoaded = true
+		if msg.err != nil {
+			m.notice = msg.err
+		}
+		if m.step == stepProjectSelect && m.selectedProj.ID == "" && !m.projectSearch.Focused() {
+			m.cursor = m.selectedProjectIndex()
+		}
+		return m, nil
+
+	// Workspaces were fetched - use the configured or only one, otherwise ask
+	case workspacesMsg:
+		m.workspaces = msg
+
+		// A refresh after the saved list just updates the names
+		if m.workspaceID != "" {
+			if ws, ok := api.FindWorkspace(m.workspaces, m.workspaceID); ok {
+				m.workspaceName = ws.Name
+			}
+			return m, nil
+		}
+		if m.step == stepWorkspaceSelect {
+			m.cursor = min(m.cursor, max(len(m.workspaces)-1, 0))
+			return m, nil
+		}
+
+		if m.workspaceKey != "" {
+			if ws, ok := api.FindWorkspace(m.workspaces, m.workspaceKey); ok {
+				return m.selectWorkspace(ws)
+			}
+			m.notice = fmt.Errorf("CLOCKIFY_WORKSPACE %q doesn't match any of your workspaces", m.workspaceKey)
+		} else if len(m.workspaces) == 1 {
+			return m.selectWorkspace(m.workspaces[0])
+		}
+		m.step = stepWorkspaceSelect
+		m.cursor = 0
+		for i, ws := range m.workspaces {
+			if ws.ID == m.defaultWorkspace {
+				m.cursor = i
+			}
+		}
+		return m, nil
+
 	// Projects were fetched successfully
+	// Saved projects are shown until the refresh arrives
 	case projectsMsg:
+		if m.projects != nil {
+			return m, nil
+		}
 		m.projects = msg
+		m.projectsCached = true
+		if m.step == stepProjectSelect && m.selectedProj.ID == "" && !m.projectSearch.Focused() {
+			m.cursor = m.selectedProjectIndex()
+		}
 		return m, nil
 
+	// Current projects arrived - swap them in without moving the cursor
+	case projectsRefreshedMsg:
+		m.projectsCached = false
+		return m.mergeProjects(msg), nil
+
 	// Tasks were fetched successfully
 	case tasksMsg:
 		m.tasks = msg
 		return m, nil
 
-	// An error occurred
+	// The selected project's tasks were fetched
+	// Projects without tasks skip the task picker entirely
+	case projectTasksMsg:
+		if msg.projectID != m.selectedProj.ID {
+			return m, nil
+		}
+		m.projectTasks = msg.tasks
+		m.projectTasksFor = msg.projectID
+		m.loadingProjectTasks = false
+		if m.step == stepProjectTaskSelect && len(m.projectTasks) == 0 {
+			return m.afterProject()
+		}
+		return m, nil
+
+	// Tags were fetched successfully
+	case tagsMsg:
+		m.tags = msg
+		return m, nil
+
+	// A tag typed into the search was created - select it right away
+	case tagCreatedMsg:
+		m.tags = append(m.tags, msg.tag)
+		if !m.hasTag(msg.tag.ID) {
+			m.selectedTags = append(m.selectedTags, msg.tag.ID)
+		}
+		return m, nil
+
+	// The selected day's entries were fetched
+	case entriesMsg:
+		m.entries = msg
+		m.loadingEntries = false
+		m.entriesErr = nil
+		if m.step == stepEntryList && m.cursor >= len(m.entries) {
+			m.cursor = max(0, len(m.entries)-1)
+		}
+		return m, nil
+
+	// The selected day's entries couldn't be fetched - durations stay blocked,
+	// since without the entries there's no telling where one should start
+	// A cancelled fetch was replaced by another, which is still loading
+	case entriesErrMsg:
+		if api.IsCanceled(msg.err) {
+			return m, nil
+		}
+		m.loadingEntries = false
+		m.entriesErr = msg.err
+		if m.step != stepTimeInput {
+			m.notice = msg.err // The time input explains it next to the duration instead
+		}
+		return m, nil
+
+	// Finished checking for a running timer - only interrupt if the user hasn't started yet
+	case runningEntryMsg:
+		if msg.entry != nil {
+			m.runningEntry = msg.entry
+			if m.step == stepDateSelect {
+				m.step = stepRunningFound
+			}
+		}
+		return m, nil
+
+	// A new timer was started - switch to the ticking screen
+	case timerStartedMsg:
+		m.submitting = false
+		m.submitErr = nil
+		m.runningEntry = msg.entry
+		saveCmd := m.recordProjectUse()
+		updated, cmd := m.showTimer()
+		return updated, tea.Batch(cmd, saveCmd)
+
+	// The timer was stopped - go back to the date screen so the user can carry on
+	case timerStoppedMsg:
+		m.runningEntry = nil
+		m.timerMode = false
+		m.timerStopped = true
+		m.date = time.Now().In(m.location)
+		m.step = stepDateSelect
+		m.cursor = 0
+		return m, nil
+
+	// Keep ticking only while the timer screen is visible
+	case tickMsg:
+		if m.step != stepTimer {
+			m.ticking = false
+			return m, nil
+		}
+		m.now = time.Time(msg)
+		return m, tick()
+
+	// A week of entries was fetched - ignore it if the user already moved on
+	case weekEntriesMsg:
+		if msg.weekStart.Equal(m.weekStart) {
+			m.weekEntries = msg.entries
+			m.loadingWeek = false
+		}
+		return m, nil
+
+	// An entry was deleted - refresh the list
+	case deleteSuccessMsg:
+		m.deleting = false
+		m.step = stepEntryList
+		m.loadingEntries = true
+		m.entriesErr = nil
+		ctx := m.screenContext()
+		return m, fetchTimeEntries(ctx, m.client, m.workspaceID, m.userID, m.date)
+
+	// Deleting failed - stay on the confirm screen so the user can try again
+	case deleteErrMsg:
+		m.deleting = false
+		if !api.IsCanceled(msg.err) {
+			m.notice = msg.err
+		}
+		return m, nil
+
+	// Loading user info or projects failed
+	case loadErrMsg:
+		// Until fetchUserInfo succeeds a rejected API key can't be fixed
+		// from inside the app, so exit
+		if !m.userChecked && api.IsAuth(msg.err) {
+			if m.profileName != "" {
+				m.err = fmt.Errorf("%w\nRun `clockify-tracker --profile %s login` to replace the API key", msg.err, m.profileName)
+				return m.quit()
+			}
+			m.err = fmt.Errorf("%w\nRun `clockify-tracker login` to replace the API key", msg.err)
+			return m.quit()
+		}
+		if !api.IsCanceled(msg.err) {
+			m.loadErr = msg.err
+			m.failedLoads = append(m.failedLoads, msg.retry)
+		}
+		return m, nil
+
+	// Submitting failed - keep everything and let the user retry from the confirm screen
+	case submitErrMsg:
+		m.submitting = false
+		if api.IsCanceled(msg.err) {
+			return m, nil
+		}
+		m.submitErr = msg.err
+		m.returnToConfirm = false
+		m.step = stepConfirm
+		return m, nil
+
+	// A background operation failed - show it and carry on
+	// Requests cancelled because the user moved on aren't worth mentioning
+	// A replacement fetch may already be running, so leave the loading flags alone
 	case errMsg:
-		m.err = msg
-		return m, tea.Quit // Quit the program on error
+		if api.IsCanceled(msg) {
+			return m, nil
+		}
+		m.notice = msg
+		m.loadingWeek = false
+		m.loadingProjectTasks = false
+		return m, nil
 
 	// Time entry was created successfully
+	// Stay open so the user can log another entry without re-fetching everything
 	case submitSuccessMsg:
+		m.submitting = false
+		m.submitErr = nil
 		m.success = true
+		m.queued = msg.queued
+		if msg.queued {
+			m.pending = msg.pending
+		}
 		m.step = stepComplete
-		return m, tea.Quit // Quit after success
+		return m, m.recordProjectUse()
 
 	// Window was resized (we don't handle this yet)
 	case tea.WindowSizeMsg:
 		return m, nil
 	}
 
-	return m, nil
+	// Anything else (e.g., cursor blinks) belongs to the focused text input
+	return m.handleTextInput(msg)
 }
 
 // handleKeyPress processes all keyboard input
+// Rebound keys are translated first, so the cases below use the default keys
 func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	switch m.keys.resolve(msg.String()) {
 
 	// Quit keys - always available
 	case "ctrl+c":
-		return m, tea.Quit
+		return m.quit()
 
 	// Quit keys - always available unless in search
 	case "q":
 		if m.step == stepProjectSelect && m.projectSearch.Focused() {
 			return m, nil
 		}
-		return m, tea.Quit
+		return m.quit()
+
+	// 'f' - star or unstar the highlighted project
+	case "f":
+		if m.step == stepProjectSelect {
+			return m.toggleFavorite()
+		}
+
+	// 'b' - flip the entry's billable status
+	case "b":
+		if m.step == stepConfirm && !m.submitting {
+			m.billable = !m.billable
+		}
+
+	// Space - toggle the highlighted tag
+	case " ":
+		if m.step == stepTagSelect {
+			filteredTags := m.filterTags()
+			if m.cursor < len(filteredTags) {
+				m.toggleTag(filteredTags[m.cursor].ID)
+			}
+		}
 
 	case "t":
 		if m.step == stepDateSelect {
-			m.date = time.Now() // Default to today
+			m.date = time.Now().In(m.location) // Default to today
+		}
+		if m.step == stepWeekView {
+			return m.showWeek(utils.StartOfWeek(time.Now().In(m.location)))
+		}
+
+	// 'r' - retry whatever failed last
+	case "r":
+		if m.loadErr != nil {
+			return m.retryLoad()
+		}
+		if m.step == stepConfirm && m.submitErr != nil {
+			return m.submit()
+		}
+
+	// 'w' - open the weekly timesheet for the selected date's week
+	case "w":
+		if m.step == stepDateSelect && m.workspaceID != "" {
+			m.step = stepWeekView
+			m.returnToConfirm = false // Leaving for the timesheet abandons a half-changed entry
+			return m.showWeek(utils.StartOfWeek(m.date))
+		}
+
+	// 'e' - list the selected day's entries for editing
+	case "e":
+		if m.step == stepDateSelect && m.workspaceID != "" {
+			m.step = stepEntryList
+			m.cursor = 0
+			m.entries = nil
+			m.loadingEntries = true
+			m.entriesErr = nil
+			ctx := m.screenContext()
+			return m, fetchTimeEntries(ctx, m.client, m.workspaceID, m.userID, m.date)
+		}
+
+	// 's' - start a new timer, or stop the running one
+	case "s":
+		switch m.step {
+		case stepDateSelect:
+			if m.runningEntry == nil {
+				// A timer is a new entry, not the one being changed
+				m.timerMode = true
+				m.returnToConfirm = false
+				m.date = time.Now().In(m.location) // Timers always start now
+				m.step = stepProjectSelect
+				m.cursor = 0
+			}
+		case stepTimer, stepRunningFound:
+			return m, stopTimer(m.ctx, m.client, m.workspaceID, m.userID)
+		}
+
+	// 'v' - view the running timer
+	case "v":
+		if m.runningEntry != nil && (m.step == stepRunningFound || m.step == stepDateSelect) {
+			return m.showTimer()
+		}
+
+	// 'd' - ask before deleting the highlighted entry
+	case "d":
+		if m.step == stepEntryList && m.cursor < len(m.entries) {
+			m.step = stepDeleteConfirm
+		}
+
+	// 'y' - confirm deletion
+	// Ignored while a delete is in flight, so a second press can't send another one
+	case "y":
+		if m.step == stepDeleteConfirm && m.cursor < len(m.entries) && !m.deleting {
+			m.deleting = true
+			return m, deleteTimeEntry(m.ctx, m.client, m.workspaceID, m.entries[m.cursor].ID)
+		}
+
+	// 'n' - cancel deletion, or log the next entry on the same project
+	case "n":
+		if m.step == stepDeleteConfirm {
+			m.step = stepEntryList
+		}
+		if m.step == stepComplete && m.success {
+			return m.logAnother(true)
+		}
+
+	// 'a' - log another entry, picking a project again
+	case "a":
+		if m.step == stepComplete && m.success {
+			return m.logAnother(false)
+		}
+
+	// Escape or shift+tab - go back one screen
+	case "esc", "shift+tab":
+		switch m.step {
+		case stepProjectSelect, stepProjectTaskSelect, stepTimeInput, stepTaskInput, stepTagSelect, stepConfirm:
+			return m.goBack()
+		case stepEntryList:
+			m.cancelScreen()
+			m.step = stepDateSelect
+			m.cursor = 0
+		case stepDeleteConfirm:
+			m.step = stepEntryList
+		case stepWeekView:
+			m.cancelScreen()
+			m.step = stepDateSelect
+		case stepTimer, stepRunningFound:
+			// The timer keeps running in Clockify
+			m.step = stepDateSelect
+			m.timerMode = false
 		}
 
 	// Up arrow or 'k' (vim style) - move cursor up in lists
@@ -132,6 +592,12 @@ func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.cursor--
 			}
 		}
+		if m.step == stepEntryList && m.cursor > 0 {
+			m.cursor--
+		}
+		if (m.step == stepTagSelect || m.step == stepProjectTaskSelect || m.step == stepWorkspaceSelect || m.step == stepProfileSelect) && m.cursor > 0 {
+			m.cursor--
+		}
 
 	// Down arrow or 'j' (vim style) - move cursor down in lists
 	case "down", "j":
@@ -141,6 +607,22 @@ func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 		}
+		if m.step == stepEntryList && m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+		if m.step == stepTagSelect && m.cursor < len(m.filterTags())-1 {
+			m.cursor++
+		}
+		if m.step == stepWorkspaceSelect && m.cursor < len(m.workspaces)-1 {
+			m.cursor++
+		}
+		if m.step == stepProfileSelect && m.cursor < len(m.profiles)-1 {
+			m.cursor++
+		}
+		// The task picker's first row is "no task"
+		if m.step == stepProjectTaskSelect && m.cursor < len(m.projectTasks) {
+			m.cursor++
+		}
 
 	// Forward slash - focus search
 	case "/":
@@ -148,18 +630,34 @@ func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.projectSearch.Focus()
 			return m, textinput.Blink
 		}
+		if m.step == stepTagSelect {
+			m.tagSearch.Focus()
+			return m, textinput.Blink
+		}
+
+	// Number keys on the confirm screen - jump to a single field to change it
+	case "1", "2", "3", "4", "5":
+		if m.step == stepConfirm {
+			return m.editField(msg.String())
+		}
 
 	// Left arrow or 'h' (vim style) - previous day
 	case "left", "h":
 		if m.step == stepDateSelect {
 			m.date = m.date.AddDate(0, 0, -1)
 		}
+		if m.step == stepWeekView {
+			return m.showWeek(m.weekStart.AddDate(0, 0, -7))
+		}
 
 	// Right arrow or 'l' (vim style) - next day
 	case "right", "l":
 		if m.step == stepDateSelect {
 			m.date = m.date.AddDate(0, 0, 1)
 		}
+		if m.step == stepWeekView {
+			return m.showWeek(m.weekStart.AddDate(0, 0, 7))
+		}
 
 	// Enter key - confirm current step and move to next
 	case "enter":
@@ -173,12 +671,37 @@ func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) handleEnter() (tea.Model, tea.Cmd) {
 	switch m.step {
 
+	// Profile picked - the default .env key is already loaded, others are read first
+	case stepProfileSelect:
+		if m.cursor < len(m.profiles) {
+			if m.profiles[m.cursor] == "" {
+				return m.useProfile("", m.client, m.workspaceKey, m.accountKey)
+			}
+			return m, loadProfile(m.profiles[m.cursor])
+		}
+
+	// Workspace picked - load everything that lives in it
+	case stepWorkspaceSelect:
+		if m.cursor < len(m.workspaces) {
+			return m.selectWorkspace(m.workspaces[m.cursor])
+		}
+
 	// Date selected - move to project selection
 	case stepDateSelect:
+		if m.returnToConfirm {
+			// A new date can change what the time input resolves to, so re-check it
+			return m.enterTimeInput()
+		}
 		m.step = stepProjectSelect
-		m.cursor = 0 // Reset cursor to top of project list
+		m.cursor = m.selectedProjectIndex() // Keep the previous choice highlighted
 		return m, nil
 
+	// Entry picked - walk through the normal steps with its values filled in
+	case stepEntryList:
+		if m.cursor < len(m.entries) {
+			return m.startEditing(m.entries[m.cursor])
+		}
+
 	// Project selected - move to time input
 	case stepProjectSelect:
 		if m.projectSearch.Focused() {
@@ -187,30 +710,69 @@ func (m model) handleEnter() (tea.Model, tea.Cmd) {
 		}
 		filteredProjects := m.filterProjects()
 		if len(filteredProjects) > 0 && m.cursor < len(filteredProjects) {
-			m.selectedProj = filteredProjects[m.cursor] // Save selected project
-			m.step = stepTimeInput
-			m.timeRange.Focus()       // Focus the time input field
-			return m, textinput.Blink // Start cursor blinking in text input
+			return m.selectProject(filteredProjects[m.cursor])
+		}
+
+	// Clockify task picked (or "no task") - carry on as if the project was just chosen
+	case stepProjectTaskSelect:
+		if m.loadingProjectTasks {
+			return m, nil
+		}
+		m.selectedTask = ""
+		if m.cursor > 0 && m.cursor <= len(m.projectTasks) {
+			m.selectedTask = m.projectTasks[m.cursor-1].ID
 		}
+		return m.afterProject()
 
 	// Time entered - move to task input
 	case stepTimeInput:
-		if m.timeRange.Value() != "" { // Only proceed if they entered something
+		// Only proceed once the input parses - the error is already shown live
+		// A duration waits for the day's entries, since they decide where it starts
+		// The resolved times are kept so a late-arriving entry list can't shift them
+		if (m.loadingEntries || m.entriesErr != nil) && !timeparse.IsRange(m.timeRange.Value()) {
+			return m, nil
+		}
+		if start, end, err := m.parseTimeInput(); err == nil {
+			m.startTime, m.endTime = start, end
+			if m.returnToConfirm {
+				return m.backToConfirm()
+			}
 			m.step = stepTaskInput
 			m.timeRange.Blur() // Unfocus the time input
 			m.taskName.Focus() // Focus the task input field
 			return m, textinput.Blink
 		}
 
-	// Task entered - move to confirmation
+	// Task entered - move to tag selection
 	case stepTaskInput:
 		if m.taskName.Value() != "" { // Only proceed if they entered something
-			m.step = stepConfirm
+			if m.returnToConfirm {
+				return m.backToConfirm()
+			}
+			m.taskName.Blur()
+			m.suggestion = 0
+			m.step = stepTagSelect
+			m.cursor = 0
+			return m, nil
 		}
 
+	// Tags chosen - move to confirmation
+	// In the search box Enter picks the typed tag, creating it if the workspace doesn't have it
+	case stepTagSelect:
+		if m.tagSearch.Focused() {
+			return m.pickSearchedTag()
+		}
+		return m.backToConfirm()
+
 	// Confirmed - submit the entry
 	case stepConfirm:
-		return m, m.submitTimeEntry()
+		return m.submit()
+
+	// Entry submitted - Enter is a shortcut for logging another
+	case stepComplete:
+		if m.success {
+			return m.logAnother(false)
+		}
 	}
 
 	return m, nil
@@ -231,17 +793,610 @@ func (m model) handleTextInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.step == stepProjectSelect && m.projectSearch.Focused() {
+		m.projectSearch, cmd = m.projectSearch.Update(msg)
+		return m, cmd
+	}
+
+	if m.step == stepTagSelect && m.tagSearch.Focused() {
+		m.tagSearch, cmd = m.tagSearch.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// handleSuggestionKey moves through the task suggestions or accepts one
+// It reports false for keys that belong to the text input
+func (m model) handleSuggestionKey(msg tea.KeyMsg) (model, bool) {
+	suggestions := m.taskSuggestions()
+	if len(suggestions) == 0 {
+		return m, false
+	}
+
+	switch msg.String() {
+	case "up":
+		if m.suggestion > 0 {
+			m.suggestion--
+		}
+		return m, true
+	case "down":
+		if m.suggestion < len(suggestions)-1 {
+			m.suggestion++
+		}
+		return m, true
+	case "tab":
+		if m.suggestion < len(suggestions) {
+			m.taskName.SetValue(suggestions[m.suggestion].text)
+			m.taskName.CursorEnd()
+			m.suggestion = 0
+		}
+		return m, true
+	}
+	return m, false
+}
+
+// enterTimeInput moves to the time input and fetches the day's entries,
+// since durations like "2h" start after the day's last entry
+func (m model) enterTimeInput() (tea.Model, tea.Cmd) {
+	m.step = stepTimeInput
+	m.timeRange.Focus() // Focus the time input field
+
+	// When editing, the list we came from already holds this day's entries
+	if m.editingEntry != "" {
+		return m, textinput.Blink // Start cursor blinking in text input
+	}
+
+	m.entries = nil
+	m.loadingEntries = true
+	m.entriesErr = nil
+	ctx := m.screenContext()
+	return m, tea.Batch(textinput.Blink, fetchTimeEntries(ctx, m.client, m.workspaceID, m.userID, m.date))
+}
+
+// backToConfirm finishes editing a field and shows the confirm screen
+func (m model) backToConfirm() (tea.Model, tea.Cmd) {
+	m.returnToConfirm = false
+	m.timeRange.Blur()
+	m.taskName.Blur()
+	m.tagSearch.Blur()
+	m.suggestion = 0
+	m.step = stepConfirm
+	return m, nil
+}
+
+// goBack returns to the previous step, keeping everything entered so far
+func (m model) goBack() (tea.Model, tea.Cmd) {
+	// Backing out of a single-field edit falls back to the normal step order
+	m.returnToConfirm = false
+	m.submitErr = nil
+	m.cancelScreen() // e.g., the day's entries fetched for the time input
+
+	switch m.step {
+	case stepProjectSelect:
+		m.projectSearch.Blur() // Keep the query so it's still there if they come back
+		switch {
+		case m.editingEntry != "":
+			// Editing started from the entry list, so return there
+			m.editingEntry = ""
+			m.step = stepEntryList
+			m.cursor = 0
+		case m.timerMode:
+			m.timerMode = false
+			m.step = stepDateSelect
+		default:
+			m.step = stepDateSelect
+		}
+		return m, nil
+
+	case stepProjectTaskSelect:
+		m.step = stepProjectSelect
+		m.cursor = m.selectedProjectIndex()
+		return m, nil
+
+	case stepTimeInput:
+		m.timeRange.Blur()
+		return m.backToProject()
+
+	case stepTaskInput:
+		m.taskName.Blur()
+		if m.timerMode {
+			return m.backToProject()
+		}
+		m.step = stepTimeInput
+		m.timeRange.Focus()
+		return m, textinput.Blink
+
+	case stepTagSelect:
+		m.tagSearch.Blur()
+		m.step = stepTaskInput
+		m.taskName.Focus()
+		return m, textinput.Blink
+
+	case stepConfirm:
+		m.step = stepTagSelect
+		m.cursor = 0
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// editField jumps from the confirm screen to a single field
+// Once that field is entered, Enter returns straight to the confirm screen
+func (m model) editField(key string) (tea.Model, tea.Cmd) {
+	m.submitErr = nil
+	// Numbered in the order the confirm screen lists the fields
+	switch key {
+	case "1":
+		m.step = stepProjectSelect
+		m.cursor = m.selectedProjectIndex()
+	case "2":
+		if m.timerMode || m.editingEntry != "" {
+			return m, nil // Timers always start today, and edits keep their date
+		}
+		m.step = stepDateSelect
+	case "3":
+		if m.timerMode {
+			return m, nil // Timers start now, there's no time to edit
+		}
+		m.returnToConfirm = true
+		return m.enterTimeInput()
+	case "4":
+		m.step = stepTaskInput
+		m.taskName.Focus()
+		m.returnToConfirm = true
+		return m, textinput.Blink
+	case "5":
+		m.step = stepTagSelect
+		m.cursor = 0
+	}
+
+	m.returnToConfirm = true
 	return m, nil
 }
 
+// useProfile logs in with the chosen profile's client and moves on to date selection
+func (m model) useProfile(name string, client *api.Client, workspaceKey, accountKey string) (tea.Model, tea.Cmd) {
+	m.profileName = name
+	m.client = client
+	m.workspaceKey = workspaceKey
+	m.accountKey = accountKey
+	m.failedLoads = nil // They were for the previous account
+	m.step = stepDateSelect
+	m.cursor = 0
+	return m, m.loadUser()
+}
+
+// loadUser sends the saved user info first, if there is any, then fetches it fresh
+func (m model) loadUser() tea.Cmd {
+	return tea.Sequence(
+		cachedUserInfo(m.accountKey, m.cacheTTL),
+		fetchUserInfo(m.ctx, m.client, m.accountKey),
+	)
+}
+
+// accountCacheKey is the cache key for the user info of config's API key
+// "" when caching is turned off
+func (m model) accountCacheKey(config *utils.Config) string {
+	if m.cacheTTL <= 0 {
+		return ""
+	}
+	return cache.AccountKey(config.APIKey, config.BaseURL)
+}
+
+// cacheKey builds the disk cache key for parts, or "" when caching is turned off
+func (m model) cacheKey(parts ...string) string {
+	if m.cacheTTL <= 0 {
+		return ""
+	}
+	return cache.Key(parts...)
+}
+
+// mergeProjects swaps in a fresh project list while keeping the highlighted
+// project highlighted, so a background refresh doesn't move the cursor
+func (m model) mergeProjects(projects []api.Project) model {
+	matches := m.rankProjects()
+	if m.step != stepProjectSelect || m.cursor >= len(matches) {
+		first := m.projects == nil
+		m.projects = projects
+		if m.step == stepProjectSelect && first && m.selectedProj.ID == "" && !m.projectSearch.Focused() {
+			m.cursor = m.selectedProjectIndex()
+		}
+		return m
+	}
+
+	// Prefer the same row in the same section; a project can be listed twice
+	current := matches[m.cursor]
+	m.projects = projects
+	matches = m.rankProjects()
+	found := -1
+	for i, match := range matches {
+		if match.project.ID != current.project.ID {
+			continue
+		}
+		if match.section == current.section {
+			found = i
+			break
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	switch {
+	case found >= 0:
+		m.cursor = found
+	case m.cursor >= len(matches):
+		// The project is gone - stay at the same spot, within the list
+		m.cursor = max(len(matches)-1, 0)
+	}
+	return m
+}
+
+// selectWorkspace switches to a workspace and fetches its projects, tasks,
+// tags and running timer in parallel
+func (m model) selectWorkspace(ws api.Workspace) (tea.Model, tea.Cmd) {
+	m.workspaceID = ws.ID
+	m.workspaceName = ws.Name
+	m.step = stepDateSelect
+	m.cursor = 0
+
+	// Saved copies are shown right away, then replaced by the fetches that follow them
+	projectsKey := m.cacheKey("projects", m.workspaceID)
+	tasksKey := m.cacheKey("tasks", m.workspaceID, m.userID)
+	tagsKey := m.cacheKey("tags", m.workspaceID)
+	return m, tea.Batch(
+		tea.Sequence(
+			loadCached(projectsKey, m.cacheTTL, func(p []api.Project) tea.Msg { return projectsMsg(p) }),
+			fetchProjects(m.ctx, m.client, m.workspaceID, projectsKey),
+		),
+		tea.Sequence(
+			loadCached(tasksKey, m.cacheTTL, func(t []api.TaskUsage) tea.Msg { return tasksMsg(t) }),
+			fetchTasks(m.ctx, m.client, m.workspaceID, m.userID, tasksKey),
+		),
+		tea.Sequence(
+			loadCached(tagsKey, m.cacheTTL, func(t []api.Tag) tea.Msg { return tagsMsg(t) }),
+			fetchTags(m.ctx, m.client, m.workspaceID, tagsKey),
+		),
+		fetchRunningEntry(m.ctx, m.client, m.workspaceID, m.userID),
+	)
+}
+
+// selectProject saves the chosen project and shows its Clockify tasks if it has any
+// The tasks are fetched once per project; the picker waits for them if needed
+func (m model) selectProject(proj api.Project) (tea.Model, tea.Cmd) {
+	// Switching projects resets everything that depends on the project,
+	// while picking the same one again keeps a billable status the user changed
+	if proj.ID != m.selectedProj.ID {
+		m.selectedTask = "" // Tasks belong to a single project
+		m.billable = proj.Billable
+	}
+	m.selectedProj = proj
+
+	if m.projectTasksFor == proj.ID {
+		if len(m.projectTasks) == 0 {
+			return m.afterProject()
+		}
+		m.step = stepProjectTaskSelect
+		m.cursor = m.selectedTaskIndex()
+		return m, nil
+	}
+
+	m.projectTasks = nil
+	m.projectTasksFor = ""
+	m.loadingProjectTasks = true
+	m.step = stepProjectTaskSelect
+	m.cursor = 0
+	ctx := m.screenContext()
+	return m, fetchProjectTasks(ctx, m.client, m.workspaceID, proj.ID)
+}
+
+// afterProject moves on from project (and task) selection to the next step
+func (m model) afterProject() (tea.Model, tea.Cmd) {
+	if m.returnToConfirm {
+		return m.backToConfirm()
+	}
+	if m.timerMode {
+		// Timers start now, so there's no time range to ask for
+		m.step = stepTaskInput
+		m.taskName.Focus()
+		return m, textinput.Blink
+	}
+	return m.enterTimeInput()
+}
+
+// backToProject steps back to the task picker, or to the project list
+// when the selected project has no tasks
+func (m model) backToProject() (tea.Model, tea.Cmd) {
+	if m.projectTasksFor == m.selectedProj.ID && len(m.projectTasks) > 0 {
+		m.step = stepProjectTaskSelect
+		m.cursor = m.selectedTaskIndex()
+		return m, nil
+	}
+	m.step = stepProjectSelect
+	m.cursor = m.selectedProjectIndex()
+	return m, nil
+}
+
+// selectedTaskIndex finds the cursor row of the chosen task in the picker
+// Row 0 is "no task", so the tasks start at 1
+func (m model) selectedTaskIndex() int {
+	for i, task := range m.projectTasks {
+		if task.ID == m.selectedTask {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// selectedProjectIndex finds the selected project in the filtered list
+// so the cursor lands back on it; falls back to the top of the list
+// Before anything was picked this session, the configured default project is
+// highlighted, or else the most recently used one
+func (m model) selectedProjectIndex() int {
+	projectID := m.selectedProj.ID
+	if projectID == "" {
+		projectID = m.projectHistory.MostRecent()
+		for _, proj := range m.projects {
+			if m.defaultProject != "" && (proj.ID == m.defaultProject || strings.EqualFold(proj.Name, m.defaultProject)) {
+				projectID = proj.ID
+				break
+			}
+		}
+	}
+	for i, proj := range m.filterProjects() {
+		if proj.ID == projectID {
+			return i
+		}
+	}
+	return 0
+}
+
+// toggleFavorite stars or unstars the highlighted project and saves the change
+// The cursor stays on the same project in the same section of the list
+func (m model) toggleFavorite() (tea.Model, tea.Cmd) {
+	matches := m.rankProjects()
+	if !m.historyLoaded || m.cursor >= len(matches) {
+		return m, nil
+	}
+	current := matches[m.cursor]
+	m.projectHistory.ToggleFavorite(current.project.ID)
+
+	// Unstarring a project in the favorites section removes that row,
+	// so fall back to wherever else the project is listed
+	m.cursor = 0
+	found := false
+	for i, match := range m.rankProjects() {
+		if match.project.ID != current.project.ID {
+			continue
+		}
+		if !found || match.section == current.section {
+			m.cursor = i
+			found = true
+		}
+	}
+
+	return m, saveHistory(m.projectHistory.Clone())
+}
+
+// recordProjectUse counts a successful entry towards the project's recent usage
+func (m *model) recordProjectUse() tea.Cmd {
+	if !m.historyLoaded || m.selectedProj.ID == "" {
+		return nil
+	}
+	m.projectHistory.Use(m.selectedProj.ID, time.Now())
+	return saveHistory(m.projectHistory.Clone())
+}
+
+// logAnother resets the inputs for a new entry on the same date
+// The next start time is pre-filled with the previous entry's end time,
+// and cached projects and tasks are reused so nothing is fetched again
+func (m model) logAnother(sameProject bool) (tea.Model, tea.Cmd) {
+	m.success = false
+	m.editingEntry = ""
+	m.returnToConfirm = false
+	m.taskName.SetValue("")
+	m.timeRange.SetValue(timeparse.Format(m.endTime.In(m.date.Location())) + " - ")
+	m.timeRange.CursorEnd()
+
+	if !sameProject {
+		m.selectedTags = nil
+		m.selectedTask = ""
+		m.step = stepProjectSelect
+		m.cursor = m.selectedProjectIndex() // Start on the project just logged
+		return m, nil
+	}
+
+	// Same project - go straight to the time input, keeping the tags too
+	return m.enterTimeInput()
+}
+
+// parseTimeInput resolves the time input, which may be a range or a duration
+func (m model) parseTimeInput() (time.Time, time.Time, error) {
+	return timeparse.ParseInput(m.timeRange.Value(), m.date, m.durationAnchor())
+}
+
+// durationAnchor is where a duration like "2h" starts: right after the last
+// entry logged on the selected date, or the configured start of the day
+func (m model) durationAnchor() time.Time {
+	if last, ok := api.LastEndTime(m.entries, m.editingEntry); ok {
+		return last.In(m.date.Location())
+	}
+	dayStart, _ := timeparse.ParseTime(m.dayStart, m.date) // Validated when the config was loaded
+	return dayStart
+}
+
+// startEditing pre-fills every input from an existing entry
+// and sends the user to project selection with that project highlighted
+func (m model) startEditing(entry api.TimeEntryResponse) (tea.Model, tea.Cmd) {
+	m.editingEntry = entry.ID
+	m.returnToConfirm = false // Any entry that was half changed is abandoned
+	m.taskName.SetValue(entry.Description)
+	m.selectedTags = append([]string(nil), entry.TagIDs...)
+
+	// A running entry has no end yet, so leave that half for the user
+	start := entry.TimeInterval.Start.In(m.date.Location())
+	m.startTime, m.endTime = start, time.Time{}
+	if entry.TimeInterval.End.IsZero() {
+		m.timeRange.SetValue(timeparse.Format(start) + " - ")
+	} else {
+		end := entry.TimeInterval.End.In(m.date.Location())
+		m.endTime = end
+		m.timeRange.SetValue(timeparse.FormatRange(start, end))
+	}
+
+	m.projectSearch.SetValue("")
+	for _, proj := range m.projects {
+		if proj.ID == entry.ProjectID {
+			m.selectedProj = proj // So picking the same project keeps the entry's task
+			break
+		}
+	}
+	m.cursor = m.selectedProjectIndex()
+	m.selectedTask = entry.TaskID
+	m.billable = entry.Billable
+
+	m.step = stepProjectSelect
+	return m, nil
+}
+
+// hasTag reports whether the tag is chosen for the entry
+func (m model) hasTag(tagID string) bool {
+	for _, id := range m.selectedTags {
+		if id == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleTag adds the tag to the entry, or removes it if it's already there
+// A new slice is built each time so copies of the model never share it
+func (m *model) toggleTag(tagID string) {
+	var tags []string
+	for _, id := range m.selectedTags {
+		if id != tagID {
+			tags = append(tags, id)
+		}
+	}
+	if len(tags) == len(m.selectedTags) {
+		tags = append(tags, tagID)
+	}
+	m.selectedTags = tags
+}
+
+// pickSearchedTag handles Enter in the tag search box
+// An exact name is selected, a partial one leaves the filtered list to pick from,
+// and an unknown name is created in the workspace and selected once Clockify returns it
+func (m model) pickSearchedTag() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.tagSearch.Value())
+	m.tagSearch.Blur()
+	m.cursor = 0
+
+	for _, tag := range m.tags {
+		if strings.EqualFold(tag.Name, name) {
+			if !m.hasTag(tag.ID) {
+				m.toggleTag(tag.ID)
+			}
+			m.tagSearch.SetValue("")
+			return m, nil
+		}
+	}
+
+	if name == "" || len(m.filterTags()) > 0 {
+		return m, nil
+	}
+
+	m.tagSearch.SetValue("")
+	return m, createTag(m.ctx, m.client, m.workspaceID, name)
+}
+
+// showWeek switches the timesheet to the week starting at weekStart and fetches its entries
+func (m model) showWeek(weekStart time.Time) (tea.Model, tea.Cmd) {
+	m.weekStart = weekStart
+	m.weekEntries = nil
+	m.loadingWeek = true
+	ctx := m.screenContext()
+	return m, fetchWeekEntries(ctx, m.client, m.workspaceID, m.userID, weekStart)
+}
+
+// showTimer switches to the ticking timer screen, starting the tick loop if needed
+func (m model) showTimer() (tea.Model, tea.Cmd) {
+	m.step = stepTimer
+	m.timerMode = true
+	m.now = time.Now()
+	if m.ticking {
+		return m, nil
+	}
+	m.ticking = true
+	return m, tick()
+}
+
+// quit cancels every in-flight request and exits the program
+func (m model) quit() (tea.Model, tea.Cmd) {
+	m.cancelScreen()
+	m.cancel()
+	return m, tea.Quit
+}
+
+// screenContext cancels requests started for the previous screen and returns
+// a fresh context for the current one, so stale fetches don't keep running
+// It changes m, so call it before `return m, ...` rather than inside it - Go
+// doesn't say whether m is copied for the return before or after the call
+func (m *model) screenContext() context.Context {
+	m.cancelScreen()
+	m.screenCtx, m.screenCancel = context.WithCancel(m.ctx)
+	return m.screenCtx
+}
+
+// cancelScreen cancels requests that belong to the screen being left
+func (m *model) cancelScreen() {
+	if m.screenCancel != nil {
+		m.screenCancel()
+		m.screenCancel = nil
+	}
+}
+
+// submit sends the entry, ignoring repeat presses while a request is in flight
+func (m model) submit() (tea.Model, tea.Cmd) {
+	if m.submitting {
+		return m, nil
+	}
+	m.submitting = true
+	m.submitErr = nil
+	return m, m.submitTimeEntry()
+}
+
+// retryLoad repeats every startup fetch that failed
+// Starting offline from saved data fails them all at once, and each has to be
+// repeated - a fresh copy of the same user doesn't fetch the rest again
+func (m model) retryLoad() (tea.Model, tea.Cmd) {
+	cmds := m.failedLoads
+	m.loadErr = nil
+	m.failedLoads = nil
+	return m, tea.Batch(cmds...)
+}
+
 // submitTimeEntry creates a command to submit the time entry
+// Entries picked from the list are updated in place instead of created
 func (m model) submitTimeEntry() tea.Cmd {
-	return createTimeEntry(
-		m.apiKey,
-		m.workspaceID,
-		m.selectedProj.ID,
-		m.taskName.Value(),
-		m.timeRange.Value(),
-		m.date,
-	)
+	entry := api.TimeEntryInput{
+		ProjectID:   m.selectedProj.ID,
+		TaskID:      m.selectedTask,
+		Description: m.taskName.Value(),
+		Billable:    m.billable,
+		Start:       m.startTime,
+		End:         m.endTime,
+		TagIDs:      m.selectedTags,
+	}
+
+	if m.timerMode {
+		return startTimer(m.ctx, m.client, m.workspaceID, entry)
+	}
+
+	if m.editingEntry != "" {
+		return updateTimeEntry(m.ctx, m.client, m.workspaceID, m.editingEntry, entry)
+	}
+
+	return createTimeEntry(m.ctx, m.client, m.workspaceID, m.userID, entry)
 }
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testModel is a model that has already loaded a workspace with one project
+// Commands are never run, so nothing reaches Clockify
+func testModel(t *testing.T) model {
+	t.Helper()
+	m := New(&utils.Config{APIKey: "test-key", DayStart: "9a", Location: time.UTC})
+	m.userID = "user-1"
+	m.workspaceID = "ws-1"
+	m.projects = []api.Project{{ID: "proj-1", Name: "Website"}}
+	m.projectTasksFor = "proj-1" // No tasks, so picking the project moves straight on
+	return m
+}
+
+// send feeds msg to the model and returns the updated model
+func send(t *testing.T, m model, msg tea.Msg) model {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	return updated.(model)
+}
+
+// press sends key presses, e.g. press(t, m, "enter", "esc"); anything else is typed
+func press(t *testing.T, m model, keys ...string) model {
+	t.Helper()
+	for _, key := range keys {
+		switch key {
+		case "enter":
+			m = send(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		case "esc":
+			m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+		default:
+			m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		}
+	}
+	return m
+}
+
+func TestSubmitAfterStartingTimer(t *testing.T) {
+	m := testModel(t)
+
+	// Start a timer and leave it running
+	m = press(t, m, "s", "enter", "Standup", "enter", "enter", "enter")
+	if !m.submitting {
+		t.Fatalf("starting the timer didn't submit (step %d)", m.step)
+	}
+	m = send(t, m, timerStartedMsg{entry: &api.TimeEntryResponse{ID: "timer-1"}})
+	if m.step != stepTimer {
+		t.Fatalf("step = %d after the timer started, want the timer screen", m.step)
+	}
+	m = press(t, m, "esc")
+
+	// Log a normal entry - confirming has to submit it
+	m = press(t, m, "enter", "enter")
+	m.loadingEntries = false // The day's entries arrived
+	m = press(t, m, "9a - 10a", "enter", "Review", "enter", "enter")
+	if m.step != stepConfirm {
+		t.Fatalf("step = %d, want the confirm screen", m.step)
+	}
+	if strings.Contains(m.View(), "Saving...") {
+		t.Fatal("confirm screen says Saving... before anything was submitted")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("confirming the entry didn't submit it")
+	}
+	m = updated.(model)
+	if !m.submitting {
+		t.Fatal("confirming the entry didn't mark it as submitting")
+	}
+}
+
+func TestMergeProjectsKeepsHighlight(t *testing.T) {
+	website := api.Project{ID: "proj-1", Name: "Website"}
+	internal := api.Project{ID: "proj-2", Name: "Internal"}
+	mobile := api.Project{ID: "proj-3", Name: "Mobile App"}
+	added := api.Project{ID: "proj-4", Name: "Aardvark"}
+
+	tests := []struct {
+		name      string
+		before    []api.Project
+		cursor    int
+		refreshed []api.Project
+		want      string // ID of the highlighted project afterwards
+	}{
+		{"project added above", []api.Project{website, internal, mobile}, 1, []api.Project{added, website, internal, mobile}, "proj-2"},
+		{"project removed above", []api.Project{website, internal, mobile}, 2, []api.Project{internal, mobile}, "proj-3"},
+		{"same list", []api.Project{website, internal, mobile}, 2, []api.Project{website, internal, mobile}, "proj-3"},
+		{"highlighted project removed", []api.Project{website, internal, mobile}, 2, []api.Project{website, internal}, "proj-2"},
+	}
+	for _, tt := range tests {
+		m := testModel(t)
+		m.projects = tt.before
+		m.projectsCached = true
+		m.step = stepProjectSelect
+		m.cursor = tt.cursor
+
+		m = send(t, m, projectsRefreshedMsg(tt.refreshed))
+		if m.projectsCached {
+			t.Errorf("%s: still marked as refreshing", tt.name)
+		}
+		if len(m.projects) != len(tt.refreshed) {
+			t.Errorf("%s: %d projects, want %d", tt.name, len(m.projects), len(tt.refreshed))
+		}
+		matches := m.rankProjects()
+		if m.cursor >= len(matches) {
+			t.Errorf("%s: cursor %d is past the %d projects", tt.name, m.cursor, len(matches))
+			continue
+		}
+		if got := matches[m.cursor].project.ID; got != tt.want {
+			t.Errorf("%s: highlighted %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCachedProjectsDontReplaceFresh(t *testing.T) {
+	m := testModel(t)
+	m.projects = nil
+
+	// The cached list shows first, then the fresh one replaces it
+	m = send(t, m, projectsMsg([]api.Project{{ID: "old", Name: "Old"}}))
+	if !m.projectsCached || len(m.projects) != 1 {
+		t.Fatalf("cached projects weren't shown: %+v", m.projects)
+	}
+	m = send(t, m, projectsRefreshedMsg([]api.Project{{ID: "new", Name: "New"}, {ID: "newer", Name: "Newer"}}))
+
+	// A cached list arriving late is ignored
+	m = send(t, m, projectsMsg([]api.Project{{ID: "old", Name: "Old"}}))
+	if m.projectsCached || len(m.projects) != 2 || m.projects[0].ID != "new" {
+		t.Errorf("projects = %+v (refreshing %v), want the fresh list", m.projects, m.projectsCached)
+	}
+}
+
+func TestUserChecked(t *testing.T) {
+	authErr := loadErrMsg{err: &api.APIError{Kind: api.ErrAuth, StatusCode: 401}}
+
+	// Cached user info doesn't prove the API key works, so a rejected key still exits
+	m := New(&utils.Config{APIKey: "test-key", DayStart: "9a", Location: time.UTC})
+	m = send(t, m, userInfoMsg{workspaceID: "ws-1", userID: "user-1"})
+	if m.userChecked {
+		t.Error("cached user info marked the API key as checked")
+	}
+	m = send(t, m, authErr)
+	if m.err == nil {
+		t.Error("a rejected API key before the user was checked didn't exit")
+	}
+
+	// Once Clockify confirmed the key, a later auth error can be retried
+	m = New(&utils.Config{APIKey: "test-key", DayStart: "9a", Location: time.UTC})
+	m = send(t, m, userInfoMsg{workspaceID: "ws-1", userID: "user-1"})
+	m = send(t, m, userInfoMsg{workspaceID: "ws-1", userID: "user-1", checked: true})
+	m = send(t, m, userInfoMsg{workspaceID: "ws-1", userID: "user-1"}) // A late cached copy doesn't undo it
+	if !m.userChecked {
+		t.Fatal("fetched user info didn't mark the API key as checked")
+	}
+	m = send(t, m, authErr)
+	if m.err != nil || m.loadErr == nil {
+		t.Errorf("err = %v, loadErr = %v; want a retryable load error", m.err, m.loadErr)
+	}
+}
+
+func TestDeleteOnlyOnce(t *testing.T) {
+	m := testModel(t)
+	m.step = stepEntryList
+	m.entries = []api.TimeEntryResponse{{ID: "entry-1", ProjectID: "proj-1"}}
+	m = press(t, m, "d")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("confirming didn't delete the entry")
+	}
+	m = updated.(model)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd != nil {
+		t.Error("a second y sent another delete")
+	}
+
+	// A failed delete can be tried again
+	m = send(t, m, deleteErrMsg{err: &api.APIError{Kind: api.ErrServer, StatusCode: 502}})
+	if m.deleting || m.notice == nil || m.step != stepDeleteConfirm {
+		t.Errorf("after a failed delete: deleting = %v, notice = %v, step = %d", m.deleting, m.notice, m.step)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Error("y after a failed delete didn't try again")
+	}
+}
+
+func TestDurationWaitsForEntries(t *testing.T) {
+	m := testModel(t)
+	m = press(t, m, "enter", "enter") // Today, then the project
+	if m.step != stepTimeInput || !m.loadingEntries {
+		t.Fatalf("step = %d, loadingEntries = %v; want the time input waiting for entries", m.step, m.loadingEntries)
+	}
+	m = press(t, m, "2h")
+
+	// An unrelated failure doesn't unblock the duration
+	m = send(t, m, errMsg(&api.APIError{Kind: api.ErrServer, StatusCode: 502}))
+	if m = press(t, m, "enter"); m.step != stepTimeInput {
+		t.Fatal("a duration was accepted before the day's entries arrived")
+	}
+
+	// Neither does failing to fetch the entries - it's explained instead
+	m = send(t, m, entriesErrMsg{err: &api.APIError{Kind: api.ErrServer, StatusCode: 502}})
+	if m = press(t, m, "enter"); m.step != stepTimeInput {
+		t.Fatal("a duration was accepted without the day's entries")
+	}
+	if !strings.Contains(m.View(), "Couldn't load this day's entries") {
+		t.Error("the time input doesn't say why the duration can't be used")
+	}
+
+	// A range doesn't need the entries
+	m.timeRange.SetValue("9a - 10a")
+	if m = press(t, m, "enter"); m.step != stepTaskInput {
+		t.Errorf("step = %d after entering a range, want the task input", m.step)
+	}
+}
+
+func TestEditFromSingleFieldEdit(t *testing.T) {
+	m := testModel(t)
+	m = press(t, m, "enter", "enter")
+	m.loadingEntries = false // The day's entries arrived
+	m = press(t, m, "9a - 10a", "enter", "Review", "enter", "enter")
+	if m.step != stepConfirm {
+		t.Fatalf("step = %d, want the confirm screen", m.step)
+	}
+
+	// Change the date, but edit an existing entry instead
+	m = press(t, m, "2", "e")
+	start := time.Date(m.date.Year(), m.date.Month(), m.date.Day(), 13, 0, 0, 0, time.UTC)
+	m = send(t, m, entriesMsg{{ID: "old-1", ProjectID: "proj-1", TimeInterval: api.TimeInterval{Start: start, End: start.Add(2 * time.Hour)}}})
+	m = press(t, m, "enter")
+	if !m.startTime.Equal(start) || !m.endTime.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("editing old-1 has times %v - %v, want the entry's own", m.startTime, m.endTime)
+	}
+
+	// The edit goes through every step rather than back to the new entry's confirm screen
+	if m = press(t, m, "enter"); m.step != stepTimeInput {
+		t.Errorf("step = %d after picking the project, want the time input", m.step)
+	}
+}
+
+func TestRetryRepeatsEveryFailedLoad(t *testing.T) {
+	m := testModel(t)
+
+	// Starting offline from saved data fails every fetch
+	offline := &api.APIError{Kind: api.ErrServer, StatusCode: 503}
+	for _, name := range []string{"user", "workspaces", "projects"} {
+		m = send(t, m, loadErrMsg{err: offline, retry: func() tea.Msg { return name }})
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(model)
+	if cmd == nil {
+		t.Fatal("r didn't retry anything")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("r retried %v, want all three fetches", batch)
+	}
+	for i, want := range []string{"user", "workspaces", "projects"} {
+		if got := batch[i](); got != want {
+			t.Errorf("retry %d fetched %v, want %s", i, got, want)
+		}
+	}
+	if m.loadErr != nil || m.failedLoads != nil {
+		t.Errorf("after retrying: loadErr = %v, %d failed loads left", m.loadErr, len(m.failedLoads))
+	}
+}
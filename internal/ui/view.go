@@ -4,8 +4,16 @@ package ui
 
 import (
 	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/fuzzy"
+	"clockify-time-tracker/internal/timeparse"
+	"clockify-time-tracker/internal/utils"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // View returns a string representation of the UI
@@ -16,29 +24,149 @@ func (m model) View() string {
 		return errorStyle.Render(fmt.Sprintf("\n❌ Error: %v\n", m.err))
 	}
 
-	// Handle success state - show success message
+	// Handle success state - a submitted entry offers to log another
 	if m.step == stepComplete && m.success {
-		return successStyle.Render("\n✅ Time entry created successfully!\n\n")
+		return m.renderComplete()
 	}
 
 	// Start building the UI string
 	// We use a string builder for efficiency
-	s := titleStyle.Render("⏱️  Clockify Time Tracker") + "\n\n"
+	s := m.header()
+
+	// Recoverable errors are shown above whatever screen we're on
+	if m.loadErr != nil {
+		s += errorStyle.Render(fmt.Sprintf("⚠ Couldn't load your Clockify data: %v", m.loadErr))
+		s += fmt.Sprintf("  %s\n\n", RetryPrompt)
+	}
+	if m.notice != nil {
+		s += errorStyle.Render(fmt.Sprintf("⚠ %v", m.notice)) + "\n\n"
+	}
+	if m.timerStopped {
+		s += successStyle.Render("✅ Timer stopped successfully!") + "\n\n"
+	}
 
 	// Render different content based on current step
 	switch m.step {
+	case stepProfileSelect:
+		s += m.renderProfileSelect()
+	case stepWorkspaceSelect:
+		s += m.renderWorkspaceSelect()
 	case stepDateSelect:
 		s += m.renderDateSelect()
 	case stepProjectSelect:
 		s += m.renderProjectSelect()
+	case stepProjectTaskSelect:
+		s += m.renderProjectTaskSelect()
 	case stepTimeInput:
 		s += m.renderTimeInput()
 	case stepTaskInput:
 		s += m.renderTaskInput()
+	case stepTagSelect:
+		s += m.renderTagSelect()
 	case stepConfirm:
 		s += m.renderConfirm()
+	case stepEntryList:
+		s += m.renderEntryList()
+	case stepDeleteConfirm:
+		s += m.renderDeleteConfirm()
+	case stepTimer:
+		s += m.renderTimer()
+	case stepRunningFound:
+		s += m.renderRunningFound()
+	case stepWeekView:
+		s += m.renderWeekView()
+	}
+
+	return s
+}
+
+// header renders the app title, followed by the profile and workspace once they're chosen
+func (m model) header() string {
+	title := "⏱️  Clockify Time Tracker"
+	if m.profileName != "" {
+		title += " · " + m.profileName
+	}
+	if m.workspaceName != "" {
+		title += " · " + m.workspaceName
+	}
+	if m.pending > 0 {
+		title += fmt.Sprintf(" · ⏳ %d pending", m.pending)
+	}
+	return titleStyle.Render(title) + "\n\n"
+}
+
+// renderProfileSelect lists the profiles to log in with
+func (m model) renderProfileSelect() string {
+	s := "Select a profile:\n\n"
+
+	start, end := visibleRange(m.cursor, len(m.profiles))
+	if start > 0 {
+		s += fmt.Sprintf("  ↑ %d more above...\n", start)
+	}
+	for i := start; i < end; i++ {
+		name := m.profiles[i]
+		if name == "" {
+			name = "(default .env)"
+		}
+		if m.cursor == i {
+			s += selectedStyle.Render("❯ "+name) + "\n"
+		} else {
+			s += "  " + name + "\n"
+		}
+	}
+	if end < len(m.profiles) {
+		s += fmt.Sprintf("  ↓ %d more below...\n", len(m.profiles)-end)
+	}
+
+	s += "\n  Pass --profile or set CLOCKIFY_PROFILE to skip this step.\n"
+	s += fmt.Sprintf("\n  %s %s %s", ArrowPrompt, EnterPrompt, QuitPrompt)
+	return s
+}
+
+// renderWorkspaceSelect lists the user's workspaces, with their default one marked
+func (m model) renderWorkspaceSelect() string {
+	s := "Select a workspace:\n\n"
+
+	start, end := visibleRange(m.cursor, len(m.workspaces))
+	if start > 0 {
+		s += fmt.Sprintf("  ↑ %d more above...\n", start)
+	}
+	for i := start; i < end; i++ {
+		ws := m.workspaces[i]
+		name := ws.Name
+		if ws.ID == m.defaultWorkspace {
+			name += " (default)"
+		}
+		if m.cursor == i {
+			s += selectedStyle.Render("❯ "+name) + "\n"
+		} else {
+			s += "  " + name + "\n"
+		}
+	}
+	if end < len(m.workspaces) {
+		s += fmt.Sprintf("  ↓ %d more below...\n", len(m.workspaces)-end)
 	}
 
+	s += "\n  Set CLOCKIFY_WORKSPACE to skip this step.\n"
+	s += fmt.Sprintf("\n  %s %s %s", ArrowPrompt, EnterPrompt, QuitPrompt)
+	return s
+}
+
+// renderComplete shows the success message and how to log another entry
+func (m model) renderComplete() string {
+	s := m.header()
+	if m.queued {
+		s += successStyle.Render("💾 Clockify couldn't be reached - entry saved offline") + "\n"
+		s += "  It will be sent the next time you start the app, or run `clockify-tracker sync`.\n\n"
+	} else if m.editingEntry != "" {
+		s += successStyle.Render("✅ Time entry updated successfully!") + "\n\n"
+	} else {
+		s += successStyle.Render("✅ Time entry created successfully!") + "\n\n"
+	}
+	s += fmt.Sprintf("  %s on %s, %s - %s\n\n", m.projectLabel(), m.date.Format("Jan 2, 2006"),
+		m.startTime.Format("3:04 PM"), m.endTime.Format("3:04 PM"))
+	s += "Log another entry for this day? The start time is filled in from this one.\n\n"
+	s += fmt.Sprintf("  %s %s %s", LogAnotherPrompt, SameProjectPrompt, QuitPrompt)
 	return s
 }
 
@@ -46,7 +174,11 @@ func (m model) View() string {
 func (m model) renderDateSelect() string {
 	s := "Select date (use ←/→ to change, Enter to confirm):\n\n"
 	s += fmt.Sprintf("  📅 %s\n\n", m.date.Format("Monday, January 2, 2006"))
-	s += fmt.Sprintf("  %s %s %s %s\n", EnterPrompt, VerticalArrowPrompt, TodayPrompt, QuitPrompt)
+	timerPrompt := StartTimerPrompt
+	if m.runningEntry != nil {
+		timerPrompt = ViewTimerPrompt
+	}
+	s += fmt.Sprintf("  %s %s %s %s %s %s %s\n", EnterPrompt, VerticalArrowPrompt, TodayPrompt, EditEntriesPrompt, WeekPrompt, timerPrompt, QuitPrompt)
 	return s
 }
 
@@ -59,7 +191,12 @@ func (m model) renderProjectSelect() string {
 
 	var sb strings.Builder
 
-	sb.WriteString("Select a project:\n\n")
+	if m.projectsCached {
+		// Saved from the last run - the fresh list replaces it in place
+		sb.WriteString("Select a project (refreshing...):\n\n")
+	} else {
+		sb.WriteString("Select a project:\n\n")
+	}
 
 	// Show search input
 	sb.WriteString("🔍 " + m.projectSearch.View() + "\n\n")
@@ -69,132 +206,756 @@ func (m model) renderProjectSelect() string {
 
 	if len(filteredProjects) == 0 {
 		sb.WriteString("  No projects match your search.\n\n")
-		sb.WriteString(fmt.Sprintf("  %s %s %s", SearchPrompt, ClearPrompt, QuitPrompt))
+		sb.WriteString(fmt.Sprintf("  %s %s %s", SearchPrompt, m.escPrompt(), QuitPrompt))
 		return sb.String()
 	}
 
 	// Calculate visible range for scrolling
-	const visibleItems = 10 // Show 10 items at a time
-	start := 0
-	end := len(filteredProjects)
-
-	// If we have more projects than can fit, show a window around cursor
-	if len(filteredProjects) > visibleItems {
-		// Center the cursor in the window
-		start = m.cursor - visibleItems/2
-		end = start + visibleItems
-
-		// Adjust if we're near the beginning
-		if start < 0 {
-			start = 0
-			end = visibleItems
-		}
-
-		// Adjust if we're near the end
-		if end > len(filteredProjects) {
-			end = len(filteredProjects)
-			start = end - visibleItems
-			if start < 0 {
-				start = 0
-			}
-		}
+	start, end := visibleRange(m.cursor, len(filteredProjects))
 
-		// Show indicator if there are items above
-		if start > 0 {
-			sb.WriteString(fmt.Sprintf("  ↑ %d more above...\n", start))
-		}
+	// Show indicator if there are items above
+	if start > 0 {
+		sb.WriteString(fmt.Sprintf("  ↑ %d more above...\n", start))
 	}
 
-	// Show visible projects
+	// Show visible projects, with the characters that matched the search highlighted
+	matches := m.rankProjects()
 	for i := start; i < end; i++ {
-		proj := filteredProjects[i]
+		match := matches[i]
+
+		// Favorites and recent projects are listed above everything else under their own headings
+		if match.section != "" && (i == start || matches[i-1].section != match.section) {
+			sb.WriteString("  " + titleStyle.UnsetMarginBottom().Render(match.section) + "\n")
+		}
 
-		// Format project name with client if available
-		displayName := proj.Name
-		if proj.ClientName != "" {
-			displayName = fmt.Sprintf("%s (%s)", proj.Name, proj.ClientName)
+		star := ""
+		if m.projectHistory.IsFavorite(match.project.ID) {
+			star = " ★"
 		}
 
 		if m.cursor == i {
-			// This is the selected item -= highlight it
-			sb.WriteString(selectedStyle.Render(fmt.Sprintf("❯ %s", displayName)) + "\n")
+			// This is the selected item - highlight it
+			sb.WriteString(selectedStyle.Render("❯ ") + renderProjectMatch(match, selectedStyle) + selectedStyle.Render(star) + "\n")
 		} else {
 			// Regular rendering for unselected items
-			sb.WriteString(fmt.Sprintf("  %s\n", displayName))
+			sb.WriteString("  " + renderProjectMatch(match, lipgloss.NewStyle()) + star + "\n")
 		}
 	}
 
 	// Show indicator if there are items below
-	if len(filteredProjects) > visibleItems && end < len(filteredProjects) {
+	if end < len(filteredProjects) {
 		sb.WriteString(fmt.Sprintf("  ↓ %d more below...\n", len(filteredProjects)-end))
 	}
 
-	sb.WriteString(fmt.Sprintf("\n  %s %s %s %s %s", ArrowPrompt, EnterPrompt, SearchPrompt, ClearPrompt, QuitPrompt))
+	sb.WriteString(fmt.Sprintf("\n  %s %s %s %s %s %s", ArrowPrompt, EnterPrompt, SearchPrompt, FavoritePrompt, m.escPrompt(), QuitPrompt))
 	return sb.String()
 }
 
-// filterProjects returns projects that match the current search query
+// visibleItems is how many rows a scrolling list shows at a time
+const visibleItems = 10
+
+// visibleRange returns the [start, end) window of a list of total items to show,
+// keeping the cursor roughly centred once the list is longer than visibleItems
+func visibleRange(cursor, total int) (int, int) {
+	if total <= visibleItems {
+		return 0, total
+	}
+
+	// Center the cursor in the window
+	start := cursor - visibleItems/2
+
+	// Adjust if we're near the beginning
+	if start < 0 {
+		start = 0
+	}
+
+	// Adjust if we're near the end
+	if start+visibleItems > total {
+		start = total - visibleItems
+	}
+
+	return start, start + visibleItems
+}
+
+// escPrompt describes what Esc does on the project screen
+// It clears the search while typing, and goes back otherwise
+func (m model) escPrompt() string {
+	if m.projectSearch.Focused() {
+		return ClearPrompt
+	}
+	return BackPrompt
+}
+
+// projectMatch is a project that matched the search, with the characters to highlight
+type projectMatch struct {
+	project    api.Project
+	section    string // Heading the project is listed under ("" when there are no sections)
+	score      int
+	nameHits   []int // Rune indexes into project.Name
+	clientHits []int // Rune indexes into project.ClientName
+}
+
+// filterProjects returns projects that match the current search query, best match first
 func (m model) filterProjects() []api.Project {
-	query := strings.ToLower(strings.TrimSpace(m.projectSearch.Value()))
+	matches := m.rankProjects()
+	filtered := make([]api.Project, len(matches))
+	for i, match := range matches {
+		filtered[i] = match.project
+	}
+	return filtered
+}
+
+// recentProjects is how many recently used projects are listed above the rest
+const recentProjects = 5
+
+// rankProjects fuzzy-matches the search query against each project's name and client
+// With no query every project is returned in the order Clockify sent them,
+// after the user's favorites and recently used projects
+func (m model) rankProjects() []projectMatch {
+	query := strings.TrimSpace(m.projectSearch.Value())
 	if query == "" {
-		return m.projects
+		return m.sectionProjects()
 	}
 
-	var filtered []api.Project
+	var matches []projectMatch
 	for _, proj := range m.projects {
-		if strings.Contains(strings.ToLower(proj.Name), query) {
-			filtered = append(filtered, proj)
+		if match, ok := matchProject(query, proj); ok {
+			matches = append(matches, match)
 		}
 	}
-	return filtered
+
+	// Stable, so equally good matches keep their original order
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// sectionProjects lists favorites, then recently used projects, then every project
+// A project can appear twice - once in a section at the top and again in the full list
+func (m model) sectionProjects() []projectMatch {
+	byID := make(map[string]api.Project, len(m.projects))
+	for _, proj := range m.projects {
+		byID[proj.ID] = proj
+	}
+
+	// Projects that were archived or deleted since are skipped
+	var pinned []projectMatch
+	for _, id := range m.projectHistory.Favorites {
+		if proj, ok := byID[id]; ok {
+			pinned = append(pinned, projectMatch{project: proj, section: "Favorites"})
+		}
+	}
+	recent := 0
+	for _, id := range m.projectHistory.Recent(len(m.projectHistory.Usage), time.Now()) {
+		proj, ok := byID[id]
+		if !ok || m.projectHistory.IsFavorite(id) {
+			continue
+		}
+		pinned = append(pinned, projectMatch{project: proj, section: "Recent"})
+		if recent++; recent == recentProjects {
+			break
+		}
+	}
+
+	// No headings are needed until something is pinned
+	section := ""
+	if len(pinned) > 0 {
+		section = "All projects"
+	}
+	matches := pinned
+	for _, proj := range m.projects {
+		matches = append(matches, projectMatch{project: proj, section: section})
+	}
+	return matches
+}
+
+// matchProject tries the query against the project name on its own and together
+// with the client name in either order, keeping the best score, so both
+// "acweb" and "webac" find "Website" for the client "Acme Corp"
+func matchProject(query string, proj api.Project) (projectMatch, bool) {
+	best := projectMatch{project: proj}
+	found := false
+
+	if result, ok := fuzzy.Match(query, proj.Name); ok {
+		best.score, best.nameHits = result.Score, result.Positions
+		found = true
+	}
+	if proj.ClientName == "" {
+		return best, found
+	}
+
+	// The two halves are joined by a space, which the hit positions skip over
+	clientLen := len([]rune(proj.ClientName))
+	nameLen := len([]rune(proj.Name))
+	if result, ok := fuzzy.Match(query, proj.ClientName+" "+proj.Name); ok && (!found || result.Score > best.score) {
+		best.score, best.clientHits, best.nameHits = result.Score, nil, nil
+		for _, pos := range result.Positions {
+			if pos < clientLen {
+				best.clientHits = append(best.clientHits, pos)
+			} else {
+				best.nameHits = append(best.nameHits, pos-clientLen-1)
+			}
+		}
+		found = true
+	}
+	if result, ok := fuzzy.Match(query, proj.Name+" "+proj.ClientName); ok && (!found || result.Score > best.score) {
+		best.score, best.clientHits, best.nameHits = result.Score, nil, nil
+		for _, pos := range result.Positions {
+			if pos < nameLen {
+				best.nameHits = append(best.nameHits, pos)
+			} else {
+				best.clientHits = append(best.clientHits, pos-nameLen-1)
+			}
+		}
+		found = true
+	}
+
+	return best, found
+}
+
+// renderProjectMatch shows "Name (Client)" in the given style with matched characters highlighted
+func renderProjectMatch(match projectMatch, style lipgloss.Style) string {
+	s := highlight(match.project.Name, match.nameHits, style)
+	if match.project.ClientName != "" {
+		s += style.Render(" (") + highlight(match.project.ClientName, match.clientHits, style) + style.Render(")")
+	}
+	return s
+}
+
+// highlight renders text in style, with the runes at the given positions in matchStyle
+func highlight(text string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+
+	hit := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		hit[pos] = true
+	}
+
+	// Render runs of matched and unmatched runes together to keep the output short
+	var sb strings.Builder
+	var run []rune
+	runIsHit := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runIsHit {
+			sb.WriteString(matchStyle.Inherit(style).Render(string(run)))
+		} else {
+			sb.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if hit[i] != runIsHit {
+			flush()
+			runIsHit = hit[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return sb.String()
+}
+
+// renderProjectTaskSelect lets the user pick one of the project's Clockify tasks
+// The first row logs the entry against the project alone
+func (m model) renderProjectTaskSelect() string {
+	s := fmt.Sprintf("Project: %s\n\n", selectedStyle.Render(m.selectedProj.Name))
+
+	if m.loadingProjectTasks {
+		return s + "  Loading tasks...\n"
+	}
+
+	s += "Select a task (optional):\n\n"
+
+	// Row 0 is "no task", then one row per project task
+	rows := len(m.projectTasks) + 1
+	start, end := visibleRange(m.cursor, rows)
+	if start > 0 {
+		s += fmt.Sprintf("  ↑ %d more above...\n", start)
+	}
+	for i := start; i < end; i++ {
+		name := "(no task)"
+		if i > 0 {
+			name = m.projectTasks[i-1].Name
+		}
+		if m.cursor == i {
+			s += selectedStyle.Render("❯ "+name) + "\n"
+		} else {
+			s += "  " + name + "\n"
+		}
+	}
+	if end < rows {
+		s += fmt.Sprintf("  ↓ %d more below...\n", rows-end)
+	}
+
+	s += fmt.Sprintf("\n  %s %s %s %s", ArrowPrompt, EnterPrompt, BackPrompt, QuitPrompt)
+	return s
+}
+
+// projectLabel names the selected project, followed by its Clockify task if one was picked
+func (m model) projectLabel() string {
+	if m.selectedTask == "" {
+		return m.selectedProj.Name
+	}
+	for _, task := range m.projectTasks {
+		if task.ID == m.selectedTask {
+			return m.selectedProj.Name + " › " + task.Name
+		}
+	}
+	return m.selectedProj.Name
 }
 
 // renderTimeInput shows the time range input field
 func (m model) renderTimeInput() string {
-	s := fmt.Sprintf("Project: %s\n", selectedStyle.Render(m.selectedProj.Name))
+	s := fmt.Sprintf("Project: %s\n", selectedStyle.Render(m.projectLabel()))
 	s += fmt.Sprintf("Date: %s\n\n", m.date.Format("Jan 2, 2006"))
-	s += "Enter time range (e.g., 9a - 5p):\n\n"
+	s += "Enter time range (e.g., 9a - 5p, 13:30 - 17:00) or duration (e.g., 2h, 1h30m, 90m):\n\n"
 	s += m.timeRange.View() // Render the text input
-	s += fmt.Sprintf("\n\n  %s %s", EnterPrompt, QuitPrompt)
+
+	// Validate as the user types so mistakes show up before the confirm screen
+	input := strings.TrimSpace(m.timeRange.Value())
+	if input != "" {
+		start, end, err := m.parseTimeInput()
+		if err != nil {
+			s += "\n\n  " + errorStyle.Render("✗ "+err.Error())
+		} else if m.entriesErr != nil && !timeparse.IsRange(input) {
+			s += "\n\n  " + errorStyle.Render(fmt.Sprintf("✗ Couldn't load this day's entries, so there's no telling where %s should start: %v", input, m.entriesErr))
+			s += "\n  Enter a time range instead, or go back and return to try again."
+		} else {
+			s += fmt.Sprintf("\n\n  ✓ %s - %s (%s)", start.Format("3:04 PM"), end.Format("3:04 PM"), formatHours(end.Sub(start)))
+			if !timeparse.IsRange(input) {
+				s += m.anchorNote()
+			}
+		}
+	}
+	s += fmt.Sprintf("\n\n  %s %s %s", EnterPrompt, BackPrompt, QuitPrompt)
 	return s
 }
 
+// anchorNote explains where a duration was anchored
+func (m model) anchorNote() string {
+	if m.loadingEntries {
+		return " - checking this day's entries before it can be used..."
+	}
+	if _, ok := api.LastEndTime(m.entries, m.editingEntry); ok {
+		return " - after your last entry"
+	}
+	return " - from the start of your day"
+}
+
 // renderTaskInput shows the task description input field
 func (m model) renderTaskInput() string {
-	s := fmt.Sprintf("Project: %s\n", selectedStyle.Render(m.selectedProj.Name))
+	s := fmt.Sprintf("Project: %s\n", selectedStyle.Render(m.projectLabel()))
 	s += fmt.Sprintf("Date: %s\n", m.date.Format("Jan 2, 2006"))
-	s += fmt.Sprintf("Time: %s\n\n", m.timeRange.Value())
+	s += fmt.Sprintf("Time: %s\n\n", timeparse.FormatRange(m.startTime, m.endTime))
 	s += "Enter task description:\n\n"
 	s += m.taskName.View() // Render the text input
 
-	// Show recent tasks as suggestions if available
-	if len(m.tasks) > 0 {
-		// Show up to 3 recent tasks
-		recentCount := min(3, len(m.tasks))
-		recentTasks := m.tasks[:recentCount]
-		s += "\n\n  Recent tasks: " + strings.Join(recentTasks, ", ")
+	// Suggest previous descriptions, best first, with the typed letters highlighted
+	suggestions := m.taskSuggestions()
+	if len(suggestions) > 0 {
+		s += "\n"
+		for i, suggestion := range suggestions {
+			if i == m.suggestion {
+				s += "\n  " + selectedStyle.Render("❯ ") + highlight(suggestion.text, suggestion.hits, selectedStyle)
+			} else {
+				s += "\n    " + highlight(suggestion.text, suggestion.hits, lipgloss.NewStyle())
+			}
+		}
+		s += fmt.Sprintf("\n\n  %s %s %s %s %s", AcceptPrompt, SuggestionPrompt, EnterPrompt, BackPrompt, QuitPrompt)
+		return s
 	}
 
-	s += fmt.Sprintf("\n\n  %s %s", EnterPrompt, QuitPrompt)
+	s += fmt.Sprintf("\n\n  %s %s %s", EnterPrompt, BackPrompt, QuitPrompt)
 	return s
 }
 
+// maxSuggestions is how many task suggestions the dropdown shows
+const maxSuggestions = 5
+
+// taskSuggestion is a previous description offered in the task dropdown
+type taskSuggestion struct {
+	text  string
+	score float64 // How often and how recently it was used, favouring the selected project
+	hits  []int   // Rune indexes that matched what was typed
+}
+
+// taskSuggestions ranks previous descriptions that match what's typed
+// Each use counts for more on the selected project, and fades the longer ago it was
+func (m model) taskSuggestions() []taskSuggestion {
+	query := strings.TrimSpace(m.taskName.Value())
+	now := time.Now()
+
+	var suggestions []taskSuggestion
+	index := make(map[string]int)
+	for _, usage := range m.tasks {
+		// Nothing to suggest if it's already exactly what was typed
+		if strings.EqualFold(usage.Description, query) {
+			continue
+		}
+		result, ok := fuzzy.Match(query, usage.Description)
+		if !ok {
+			continue
+		}
+
+		weeks := now.Sub(usage.LastUsed).Hours() / (24 * 7)
+		score := float64(usage.Count) / (1 + max(weeks, 0))
+		if usage.ProjectID == m.selectedProj.ID {
+			score *= 3
+		}
+
+		// The same description may have been used on several projects
+		if i, seen := index[usage.Description]; seen {
+			suggestions[i].score += score
+			continue
+		}
+		index[usage.Description] = len(suggestions)
+		suggestions = append(suggestions, taskSuggestion{text: usage.Description, score: score, hits: result.Positions})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].score > suggestions[j].score
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// renderTagSelect shows the workspace tags with checkboxes for the chosen ones
+func (m model) renderTagSelect() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Project: %s\n", selectedStyle.Render(m.projectLabel())))
+	sb.WriteString(fmt.Sprintf("Task: %s\n\n", m.taskName.Value()))
+	sb.WriteString("Select tags (optional):\n\n")
+	sb.WriteString("🔍 " + m.tagSearch.View() + "\n\n")
+
+	filteredTags := m.filterTags()
+	query := strings.TrimSpace(m.tagSearch.Value())
+
+	switch {
+	case len(m.tags) == 0 && query == "":
+		sb.WriteString("  This workspace has no tags yet - press / and type a name to create one.\n")
+	case len(filteredTags) == 0:
+		sb.WriteString(fmt.Sprintf("  No tags match - press Enter to create %q.\n", query))
+	}
+
+	start, end := visibleRange(m.cursor, len(filteredTags))
+	if start > 0 {
+		sb.WriteString(fmt.Sprintf("  ↑ %d more above...\n", start))
+	}
+
+	for i := start; i < end; i++ {
+		tag := filteredTags[i]
+
+		box := "[ ]"
+		if m.hasTag(tag.ID) {
+			box = "[x]"
+		}
+
+		line := fmt.Sprintf("%s %s", box, tag.Name)
+		if m.cursor == i && !m.tagSearch.Focused() {
+			sb.WriteString(selectedStyle.Render("❯ "+line) + "\n")
+		} else {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	if end < len(filteredTags) {
+		sb.WriteString(fmt.Sprintf("  ↓ %d more below...\n", len(filteredTags)-end))
+	}
+
+	if m.tagSearch.Focused() {
+		enterPrompt := EnterPrompt
+		if len(filteredTags) == 0 && query != "" {
+			enterPrompt = CreateTagPrompt
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s %s %s", enterPrompt, ClearPrompt, QuitPrompt))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("\n  %s %s %s %s %s %s", ArrowPrompt, ToggleTagPrompt, SearchPrompt, ContinuePrompt, BackPrompt, QuitPrompt))
+	return sb.String()
+}
+
+// filterTags returns tags whose name contains the current search query
+func (m model) filterTags() []api.Tag {
+	query := strings.ToLower(strings.TrimSpace(m.tagSearch.Value()))
+	if query == "" {
+		return m.tags
+	}
+
+	var filtered []api.Tag
+	for _, tag := range m.tags {
+		if strings.Contains(strings.ToLower(tag.Name), query) {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
+
+// tagNames lists the chosen tags by name for display
+func (m model) tagNames() string {
+	if len(m.selectedTags) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(m.selectedTags))
+	for _, id := range m.selectedTags {
+		name := "(unknown tag)"
+		for _, tag := range m.tags {
+			if tag.ID == id {
+				name = tag.Name
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
 // renderConfirm shows the confirmation screen with all entered details
 func (m model) renderConfirm() string {
 	s := "Confirm time entry:\n\n"
-	s += fmt.Sprintf("  Project: %s\n", selectedStyle.Render(m.selectedProj.Name))
+	if m.editingEntry != "" {
+		s = "Confirm changes to time entry:\n\n"
+	}
+	s += fmt.Sprintf("  [1] Project: %s\n", selectedStyle.Render(m.projectLabel()))
+	s += fmt.Sprintf("  [2] Date: %s\n", m.date.Format("Jan 2, 2006"))
+	if m.timerMode {
+		s += "  [3] Time: starts now (live timer)\n"
+	} else {
+		s += fmt.Sprintf("  [3] Time: %s (%s)\n", timeparse.FormatRange(m.startTime, m.endTime), formatHours(m.endTime.Sub(m.startTime)))
+	}
+	s += fmt.Sprintf("  [4] Task: %s\n", m.taskName.Value())
+	s += fmt.Sprintf("  [5] Tags: %s\n", m.tagNames())
+	s += fmt.Sprintf("  [b] Billable: %s\n\n", yesNo(m.billable))
+
+	if m.submitting {
+		return s + "  Saving...\n"
+	}
+
+	if m.submitErr != nil {
+		s += errorStyle.Render(fmt.Sprintf("  ✗ Couldn't save the entry: %v", m.submitErr)) + "\n"
+		if api.KindOf(m.submitErr) == api.ErrValidation {
+			s += "  Clockify rejected the entry - change a field and try again.\n\n"
+		} else {
+			s += "  Nothing was lost - press r to try again.\n\n"
+		}
+		s += fmt.Sprintf("  %s %s %s %s %s %s", RetryPrompt, EditFieldPrompt, BillablePrompt, BackPrompt, EnterPrompt, QuitPrompt)
+		return s
+	}
+
+	s += fmt.Sprintf("  %s %s %s %s %s", EnterPrompt, EditFieldPrompt, BillablePrompt, BackPrompt, QuitPrompt)
+	return s
+}
+
+// yesNo renders a flag for display
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// renderWeekView shows a projects x days grid of logged hours for one week
+func (m model) renderWeekView() string {
+	weekEnd := m.weekStart.AddDate(0, 0, 6)
+	s := fmt.Sprintf("Week of %s - %s:\n\n", m.weekStart.Format("Jan 2"), weekEnd.Format("Jan 2, 2006"))
+	prompts := fmt.Sprintf("\n  %s %s %s %s", WeekArrowPrompt, ThisWeekPrompt, BackPrompt, QuitPrompt)
+
+	if m.loadingWeek {
+		return s + "  Loading entries...\n" + prompts
+	}
+
+	if len(m.weekEntries) == 0 {
+		return s + "  No entries logged this week.\n" + prompts
+	}
+
+	// Sum durations per project per day, remembering the order projects first appear
+	totals := make(map[string]*[7]time.Duration)
+	var projectIDs []string
+	var dayTotals [7]time.Duration
+	for _, entry := range m.weekEntries {
+		start := entry.TimeInterval.Start.In(m.weekStart.Location())
+		end := entry.TimeInterval.End
+		if end.IsZero() {
+			end = time.Now() // Count a running timer up to now
+		}
+
+		// Round because days around DST changes are 23 or 25 hours long
+		day := int(math.Round(utils.StartOfDay(start).Sub(m.weekStart).Hours() / 24))
+		if day < 0 || day > 6 {
+			continue
+		}
+
+		if totals[entry.ProjectID] == nil {
+			totals[entry.ProjectID] = &[7]time.Duration{}
+			projectIDs = append(projectIDs, entry.ProjectID)
+		}
+		totals[entry.ProjectID][day] += end.Sub(start)
+		dayTotals[day] += end.Sub(start)
+	}
+
+	const nameWidth = 24
+	const cellWidth = 7
+
+	// Header row with day names, today in bold
+	header := fmt.Sprintf("  %-*s", nameWidth, "Project")
+	for day := 0; day < 7; day++ {
+		date := m.weekStart.AddDate(0, 0, day)
+		label := fmt.Sprintf("%*s", cellWidth, date.Format("Mon 2"))
+		if date.Equal(utils.StartOfDay(time.Now().In(m.weekStart.Location()))) {
+			label = selectedStyle.Render(label)
+		}
+		header += label
+	}
+	s += header + fmt.Sprintf("%*s\n", cellWidth+1, "Total")
+
+	// One row per project
+	for _, projectID := range projectIDs {
+		name := []rune(m.projectName(projectID))
+		if len(name) > nameWidth-1 {
+			name = append(name[:nameWidth-2], '…')
+		}
+
+		var rowTotal time.Duration
+		row := fmt.Sprintf("  %-*s", nameWidth, string(name))
+		for day := 0; day < 7; day++ {
+			row += fmt.Sprintf("%*s", cellWidth, formatHours(totals[projectID][day]))
+			rowTotal += totals[projectID][day]
+		}
+		s += row + fmt.Sprintf("%*s\n", cellWidth+1, formatHours(rowTotal))
+	}
+
+	// Totals row with the weekly sum in the last column
+	var weekTotal time.Duration
+	row := fmt.Sprintf("  %-*s", nameWidth, "Total")
+	for day := 0; day < 7; day++ {
+		row += fmt.Sprintf("%*s", cellWidth, formatHours(dayTotals[day]))
+		weekTotal += dayTotals[day]
+	}
+	s += "  " + strings.Repeat("─", nameWidth+cellWidth*8) + "\n"
+	s += selectedStyle.Render(row+fmt.Sprintf("%*s", cellWidth+1, formatHours(weekTotal))) + "\n"
+
+	return s + prompts
+}
+
+// formatHours renders a duration as H:MM, or "-" when nothing was logged
+func formatHours(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// renderTimer shows the running timer with a ticking elapsed time
+func (m model) renderTimer() string {
+	entry := m.runningEntry
+	if entry == nil {
+		return "Starting timer...\n"
+	}
+
+	start := entry.TimeInterval.Start.In(m.location)
+	elapsed := m.now.Sub(start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	s := successStyle.Render("● Timer running") + "\n\n"
+	s += fmt.Sprintf("  Project: %s\n", selectedStyle.Render(m.projectName(entry.ProjectID)))
+	s += fmt.Sprintf("  Task: %s\n", entry.Description)
+	s += fmt.Sprintf("  Started: %s\n\n", start.Format("Jan 2, 3:04 PM"))
+	s += fmt.Sprintf("  Elapsed: %s\n\n", formatElapsed(elapsed))
+	s += fmt.Sprintf("  %s %s %s", StopTimerPrompt, LeaveRunningPrompt, QuitPrompt)
+	return s
+}
+
+// renderRunningFound tells the user a timer was already running when the app started
+func (m model) renderRunningFound() string {
+	entry := m.runningEntry
+	start := entry.TimeInterval.Start.In(m.location)
+
+	s := "A timer is already running:\n\n"
+	s += fmt.Sprintf("  Project: %s\n", selectedStyle.Render(m.projectName(entry.ProjectID)))
+	s += fmt.Sprintf("  Task: %s\n", entry.Description)
+	s += fmt.Sprintf("  Started: %s\n\n", start.Format("Jan 2, 3:04 PM"))
+	s += fmt.Sprintf("  %s %s %s %s", StopTimerPrompt, ViewTimerPrompt, LeaveRunningPrompt, QuitPrompt)
+	return s
+}
+
+// formatElapsed renders a duration as HH:MM:SS
+func formatElapsed(d time.Duration) string {
+	total := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total/60)%60, total%60)
+}
+
+// renderEntryList shows the entries logged on the selected date
+func (m model) renderEntryList() string {
+	s := fmt.Sprintf("Entries for %s:\n\n", m.date.Format("Monday, January 2, 2006"))
+
+	if m.loadingEntries {
+		return s + "  Loading entries...\n"
+	}
+
+	if len(m.entries) == 0 {
+		s += "  No entries logged on this day.\n\n"
+		s += fmt.Sprintf("  %s %s", BackPrompt, QuitPrompt)
+		return s
+	}
+
+	for i, entry := range m.entries {
+		line := fmt.Sprintf("%-16s %-24s %s", m.formatEntryTime(entry), m.projectName(entry.ProjectID), entry.Description)
+		if m.cursor == i {
+			s += selectedStyle.Render("❯ "+line) + "\n"
+		} else {
+			s += "  " + line + "\n"
+		}
+	}
+
+	s += fmt.Sprintf("\n  %s %s %s %s %s", ArrowPrompt, EnterPrompt, DeletePrompt, BackPrompt, QuitPrompt)
+	return s
+}
+
+// renderDeleteConfirm asks the user to confirm deleting the highlighted entry
+func (m model) renderDeleteConfirm() string {
+	entry := m.entries[m.cursor]
+	s := "Delete this time entry?\n\n"
+	s += fmt.Sprintf("  Project: %s\n", selectedStyle.Render(m.projectName(entry.ProjectID)))
 	s += fmt.Sprintf("  Date: %s\n", m.date.Format("Jan 2, 2006"))
-	s += fmt.Sprintf("  Time: %s\n", m.timeRange.Value())
-	s += fmt.Sprintf("  Task: %s\n\n", m.taskName.Value())
-	s += fmt.Sprintf("  %s %s", EnterPrompt, QuitPrompt)
+	s += fmt.Sprintf("  Time: %s\n", m.formatEntryTime(entry))
+	s += fmt.Sprintf("  Task: %s\n\n", entry.Description)
+	if m.deleting {
+		return s + "  Deleting...\n"
+	}
+	s += fmt.Sprintf("  %s %s", YesNoPrompt, QuitPrompt)
 	return s
 }
 
-// min returns the smaller of two integers
-// Helper function used for limiting the number of recent tasks shown
-func min(a, b int) int {
-	if a < b {
-		return a
+// formatEntryTime renders an entry's interval in local time, e.g. "9a - 5p"
+func (m model) formatEntryTime(entry api.TimeEntryResponse) string {
+	start := entry.TimeInterval.Start.In(m.date.Location())
+	if entry.TimeInterval.End.IsZero() {
+		return timeparse.Format(start) + " - running"
+	}
+	return timeparse.FormatRange(start, entry.TimeInterval.End.In(m.date.Location()))
+}
+
+// projectName looks up a project's name by ID for display
+func (m model) projectName(projectID string) string {
+	for _, proj := range m.projects {
+		if proj.ID == projectID {
+			return proj.Name
+		}
+	}
+	if projectID == "" {
+		return "(no project)"
 	}
-	return b
+	return "(unknown project)"
 }
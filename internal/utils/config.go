@@ -2,35 +2,200 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/timeparse"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds the application configuration
 // We only need the API key at startup - workspace and user IDs are fetched later
+// Secrets and connection settings come from the environment (.env), preferences
+// from config.toml; where both can set something, the environment wins
 type Config struct {
-	APIKey string
+	APIKey    string   // "" when there's no default key and a profile has to be picked
+	BaseURL   string   // Clockify API base URL ("" for the default)
+	Profile   string   // Name of the active profile ("" for the plain .env settings)
+	Profiles  []string // Every profile that could be picked
+	DayStart  string   // Where durations like "2h" start when nothing is logged yet that day (e.g., "9a")
+	Workspace string   // Workspace ID or name to use instead of asking ("" to ask when there are several)
+
+	// Preferences from config.toml
+	Project   string            // Project name or ID highlighted when the project list opens
+	TimeRange string            // Time input filled in for new entries ("" for none)
+	Location  *time.Location    // Time zone for dates and times
+	Theme     string            // Colour theme: "dark", "light" or "none"
+	Keys      map[string]string // Key for each rebindable action, see DefaultKeys
+	CacheTTL  time.Duration     // How old saved Clockify data may be and still be shown (0 for never)
+
+	// HTTP behaviour for API requests
+	Timeout    time.Duration // How long a single request attempt may take
+	MaxRetries int           // How many times a failed request may be retried
 }
 
-// LoadConfig loads environment variables from .env file and validates them
-// Returns a Config struct or an error if required variables are missing
-func LoadConfig() (*Config, error) {
+// ClientOptions returns the API client options the config asks for
+func (c *Config) ClientOptions() []api.Option {
+	return []api.Option{
+		api.WithTimeout(c.Timeout),
+		api.WithMaxRetries(c.MaxRetries),
+		api.WithBaseURL(c.BaseURL),
+	}
+}
+
+// ErrNoAPIKey means there's no API key anywhere, so the user hasn't set up the app yet
+var ErrNoAPIKey = errors.New("CLOCKIFY_API_KEY not set")
+
+// LoadEnv loads environment variables from the .env files
+// Variables that are already set are never overridden
+func LoadEnv() {
 	// Load .env file - ignore error if file doesn't exist (e.g., in production)
 	// The underscore _ means we're intentionally ignoring the return value
-	homedir, _ := os.UserHomeDir()
-	_ = godotenv.Load("./.env")                                   // try local .env after. Will not override.
-	_ = godotenv.Load(homedir + "/.config/clockify-tracker/.env") // Load config env first
+	_ = godotenv.Load("./.env")                           // try local .env after. Will not override.
+	_ = godotenv.Load(filepath.Join(ConfigDir(), ".env")) // Load config env first
+}
+
+// LoadConfig loads environment variables from .env file and validates them
+// Returns a Config struct or an error if required variables are missing
+// profile names a profile to use; "" falls back to CLOCKIFY_PROFILE, then the plain .env
+func LoadConfig(profile string) (*Config, error) {
+	LoadEnv()
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional config file - errors name the key so it's easy to fix
+	doc, err := readConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile(), err)
+	}
+	if err := validateConfig(doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile(), err)
+	}
+	fromFile := func(key, fallback string) string {
+		if value, ok := doc.values[key]; ok {
+			return value.value
+		}
+		return fallback
+	}
 
 	// Get the API key from environment
 	apiKey := os.Getenv("CLOCKIFY_API_KEY")
+	baseURL := os.Getenv("CLOCKIFY_BASE_URL")
+
+	// Optional workspace and project, so people in several workspaces aren't asked every time
+	workspace := os.Getenv("CLOCKIFY_WORKSPACE")
+	if workspace == "" {
+		workspace = fromFile("workspace", "")
+	}
+	project := fromFile("project", "")
+
+	// A profile is a different account, so it only uses its own connection settings
+	// The environment holds the default .env's by now, and config.toml's workspace
+	// and project were picked for the default account too
+	if profile == "" {
+		profile = os.Getenv("CLOCKIFY_PROFILE")
+	}
+	if profile != "" {
+		p, err := LoadProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		apiKey = p.APIKey
+		baseURL = p.BaseURL
+		workspace = p.Workspace
+		project = ""
+	}
+
+	// Keys saved by `login` live in the credential store instead of the env files
+	// It's only opened when needed, so a locked or unreachable store doesn't stop
+	// scripts that have the key in the environment
 	if apiKey == "" {
-		return nil, fmt.Errorf("CLOCKIFY_API_KEY not set in .env file")
+		store, err := OpenCredentialStore(fromFile("credential_store", StoreAuto))
+		if err != nil {
+			return nil, err
+		}
+		apiKey, err = store.Get(account(profile))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Without a key the UI asks which profile to use, so only fail if there are none
+	if apiKey == "" && profile != "" {
+		return nil, fmt.Errorf("profile %q has no API key: run `clockify-tracker --profile %s login`", profile, profile)
+	}
+	if apiKey == "" && len(profiles) == 0 {
+		return nil, fmt.Errorf("%w: run `clockify-tracker login` or add it to %s", ErrNoAPIKey, filepath.Join(ConfigDir(), ".env"))
+	}
+
+	// Optional default start of the working day, used to anchor durations
+	dayStart := os.Getenv("CLOCKIFY_DAY_START")
+	if dayStart == "" {
+		dayStart = fromFile("hours.start", "9a")
+	}
+	if _, err := timeparse.ParseTime(dayStart, time.Now()); err != nil {
+		return nil, fmt.Errorf("CLOCKIFY_DAY_START: %w", err)
+	}
+
+	// The working hours double as the default time range unless one is set
+	timeRange := fromFile("time_range", "")
+	if dayEnd := fromFile("hours.end", ""); timeRange == "" && dayEnd != "" {
+		timeRange = dayStart + " - " + dayEnd
+	}
+
+	// Already validated, so these can't fail
+	location, _ := time.LoadLocation(fromFile("timezone", "Local"))
+	cacheTTL, _ := time.ParseDuration(fromFile("cache_ttl", "24h"))
+
+	// Optional request timeout (e.g., "30s") and retry count for flaky connections
+	timeout := api.DefaultTimeout
+	if value := os.Getenv("CLOCKIFY_TIMEOUT"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("CLOCKIFY_TIMEOUT: %q is not a positive duration like 30s", value)
+		}
+		timeout = parsed
+	}
+
+	maxRetries := api.DefaultMaxRetries
+	if value := os.Getenv("CLOCKIFY_MAX_RETRIES"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("CLOCKIFY_MAX_RETRIES: %q is not a whole number of retries", value)
+		}
+		maxRetries = parsed
 	}
 
 	// Return the config struct
 	return &Config{
-		APIKey: apiKey,
+		APIKey:     apiKey,
+		BaseURL:    baseURL,
+		Profile:    profile,
+		Profiles:   profiles,
+		DayStart:   dayStart,
+		Workspace:  workspace,
+		Project:    project,
+		TimeRange:  timeRange,
+		Location:   location,
+		Theme:      fromFile("theme", "dark"),
+		Keys:       configKeys(doc),
+		CacheTTL:   cacheTTL,
+		Timeout:    timeout,
+		MaxRetries: maxRetries,
 	}, nil
 }
+
+// ConfigDir is where the app keeps its .env and other per-user files
+func ConfigDir() string {
+	homedir, _ := os.UserHomeDir()
+	return filepath.Join(homedir, ".config", "clockify-tracker")
+}
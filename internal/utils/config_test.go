@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigSkipsStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLOCKIFY_PROFILE", "")
+	t.Setenv("CLOCKIFY_PASSPHRASE", "")
+	t.Setenv("CLOCKIFY_API_KEY", "env-key")
+
+	// A profile exists and the encrypted store can't be read, e.g. from cron
+	writeConfig(t, "credential_store = \"file\"\n")
+	if err := CreateProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ConfigDir(), "credentials.enc"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.APIKey != "env-key" {
+		t.Errorf("APIKey = %q, want the one from the environment", config.APIKey)
+	}
+}
+
+func TestLoadConfigProfileSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLOCKIFY_PROFILE", "")
+
+	// What the default account's .env and the setup wizard leave behind
+	t.Setenv("CLOCKIFY_API_KEY", "default-key")
+	t.Setenv("CLOCKIFY_WORKSPACE", "Acme")
+	t.Setenv("CLOCKIFY_BASE_URL", "https://eu.example.com/api/v1")
+	writeConfig(t, "workspace = \"Acme\"\nproject = \"Website\"\n")
+
+	profiles := map[string]string{
+		"client": "CLOCKIFY_API_KEY=client-key\n",
+		"other":  "CLOCKIFY_API_KEY=other-key\nCLOCKIFY_WORKSPACE=Other Inc\n",
+	}
+	if err := os.MkdirAll(ProfilesDir(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range profiles {
+		if err := os.WriteFile(filepath.Join(ProfilesDir(), name+".env"), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		profile                             string
+		apiKey, workspace, baseURL, project string
+	}{
+		{"", "default-key", "Acme", "https://eu.example.com/api/v1", "Website"},
+		{"client", "client-key", "", "", ""},
+		{"other", "other-key", "Other Inc", "", ""},
+	}
+	for _, tt := range tests {
+		config, err := LoadConfig(tt.profile)
+		if err != nil {
+			t.Errorf("LoadConfig(%q) failed: %v", tt.profile, err)
+			continue
+		}
+		if config.APIKey != tt.apiKey || config.Workspace != tt.workspace || config.BaseURL != tt.baseURL || config.Project != tt.project {
+			t.Errorf("LoadConfig(%q) = key %q, workspace %q, base URL %q, project %q; want %q, %q, %q, %q",
+				tt.profile, config.APIKey, config.Workspace, config.BaseURL, config.Project, tt.apiKey, tt.workspace, tt.baseURL, tt.project)
+		}
+	}
+}
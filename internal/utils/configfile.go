@@ -0,0 +1,498 @@
+// Reads and writes the structured config file, ~/.config/clockify-tracker/config.toml
+// Only the small part of TOML the settings need is supported: [section] headers,
+// key = value lines, # comments, and "basic" or 'literal' strings
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"clockify-time-tracker/internal/timeparse"
+)
+
+// setting describes one key the config file accepts
+type setting struct {
+	key      string             // Dotted name, e.g. "hours.start"
+	help     string             // Shown by `config list`
+	validate func(string) error // nil when any string is fine
+}
+
+// settings lists every key the config file accepts, in the order `config list` shows them
+var settings = []setting{
+	{key: "workspace", help: "workspace ID or name to use instead of asking"},
+	{key: "project", help: "project name or ID highlighted when the project list opens"},
+	{key: "time_range", help: "time input filled in for new entries, e.g. \"9a - 5p\" or \"2h\"", validate: validateTimeRange},
+	{key: "timezone", help: "IANA time zone for dates and times, e.g. \"Europe/Berlin\"", validate: validateTimezone},
+	{key: "theme", help: "colour theme: dark, light or none", validate: validateTheme},
+	{key: "cache_ttl", help: "how long saved projects and user info are shown while refreshing, e.g. \"24h\" (0 turns it off)", validate: validateTTL},
+	{key: "credential_store", help: "where `login` saves API keys: auto, keyring, file or env", validate: validateStore},
+	{key: "hours.start", help: "start of the working day, where durations begin (e.g. \"9a\")", validate: validateClock},
+	{key: "hours.end", help: "end of the working day; with hours.start it's the default time range", validate: validateClock},
+}
+
+// init adds a keys.<action> setting for every action that can be rebound
+func init() {
+	actions := make([]string, 0, len(DefaultKeys))
+	for action := range DefaultKeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		settings = append(settings, setting{
+			key:      "keys." + action,
+			help:     fmt.Sprintf("key for %s (default %q)", strings.ReplaceAll(action, "_", " "), DefaultKeys[action]),
+			validate: validateKey,
+		})
+	}
+}
+
+// DefaultKeys are the keys for actions that can be rebound in the [keys] section
+var DefaultKeys = map[string]string{
+	"quit":       "q",
+	"today":      "t",
+	"retry":      "r",
+	"week":       "w",
+	"edit":       "e",
+	"timer":      "s",
+	"view_timer": "v",
+	"delete":     "d",
+	"favorite":   "f",
+	"billable":   "b",
+	"search":     "/",
+}
+
+// reservedKeys can't be rebound because they're used for navigation and answers
+var reservedKeys = map[string]bool{
+	"j": true, "k": true, "h": true, "l": true, "y": true, "n": true, "a": true,
+	"1": true, "2": true, "3": true, "4": true, "5": true, "ctrl+c": true,
+}
+
+// ConfigFile is the path of the structured config file
+func ConfigFile() string {
+	return filepath.Join(ConfigDir(), "config.toml")
+}
+
+// fileValue is one key = value line from the config file
+type fileValue struct {
+	value   string
+	comment string // Trailing "# ..." comment, kept when the value is replaced
+	line    int    // 1-based, for error messages
+}
+
+// configDoc is a parsed config file, keeping enough of the layout to edit it in place
+type configDoc struct {
+	lines    []string
+	values   map[string]fileValue // Keyed by dotted name
+	sections map[string]int       // Section name -> index of its last line ("" is the top of the file)
+}
+
+// readConfigFile loads and parses the config file; a missing file is empty
+func readConfigFile() (*configDoc, error) {
+	data, err := os.ReadFile(ConfigFile())
+	if errors.Is(err, fs.ErrNotExist) {
+		return parseConfig("")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(string(data))
+}
+
+// parseConfig parses the config file's TOML subset
+// Unknown keys and syntax errors are reported with their line number
+func parseConfig(data string) (*configDoc, error) {
+	doc := &configDoc{
+		values:   map[string]fileValue{},
+		sections: map[string]int{"": -1},
+	}
+	if data != "" {
+		doc.lines = strings.Split(strings.TrimRight(data, "\n"), "\n")
+	}
+
+	section := ""
+	for i, raw := range doc.lines {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// [section] header
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 || !isComment(line[end+1:]) {
+				return nil, fmt.Errorf("line %d: malformed section header %q", lineNo, line)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if !isBareKey(section) {
+				return nil, fmt.Errorf("line %d: invalid section name %q", lineNo, section)
+			}
+			doc.sections[section] = i
+			continue
+		}
+
+		// key = value
+		name, rest, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found || !isBareKey(name) {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", lineNo, line)
+		}
+		key := name
+		if section != "" {
+			key = section + "." + name
+		}
+		if findSetting(key) == nil {
+			return nil, fmt.Errorf("line %d: unknown key %q", lineNo, key)
+		}
+		if _, dup := doc.values[key]; dup {
+			return nil, fmt.Errorf("line %d: %s is set twice", lineNo, key)
+		}
+
+		value, comment, err := parseValue(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, key, err)
+		}
+		doc.values[key] = fileValue{value: value, comment: comment, line: lineNo}
+		doc.sections[section] = i
+	}
+
+	return doc, nil
+}
+
+// parseValue reads a quoted string, or a bare number or boolean, up to an optional comment
+// Returns the value and the comment, if any
+func parseValue(s string) (string, string, error) {
+	if s == "" {
+		return "", "", fmt.Errorf("missing value")
+	}
+
+	switch s[0] {
+	case '"':
+		// Basic string - backslash escapes are allowed
+		var sb strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch c := s[i]; c {
+			case '"':
+				if !isComment(s[i+1:]) {
+					return "", "", fmt.Errorf("unexpected text after the closing quote")
+				}
+				return sb.String(), strings.TrimSpace(s[i+1:]), nil
+			case '\\':
+				i++
+				if i == len(s) {
+					return "", "", fmt.Errorf("unterminated string")
+				}
+				switch s[i] {
+				case '"', '\\':
+					sb.WriteByte(s[i])
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				default:
+					return "", "", fmt.Errorf("unsupported escape \\%c", s[i])
+				}
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", "", fmt.Errorf("unterminated string")
+
+	case '\'':
+		// Literal string - taken as is up to the next single quote
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		if !isComment(s[end+2:]) {
+			return "", "", fmt.Errorf("unexpected text after the closing quote")
+		}
+		return s[1 : end+1], strings.TrimSpace(s[end+2:]), nil
+	}
+
+	// Bare values are only allowed for numbers and booleans, like in TOML
+	value, comment := s, ""
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		value, comment = strings.TrimSpace(s[:i]), s[i:]
+	}
+	if value == "true" || value == "false" || isInteger(value) {
+		return value, comment, nil
+	}
+	return "", "", fmt.Errorf("strings must be quoted, e.g. %q", value)
+}
+
+// GetConfigValue returns a key's value from the config file, and whether it's set
+func GetConfigValue(key string) (string, bool, error) {
+	if findSetting(key) == nil {
+		return "", false, fmt.Errorf("unknown key %q", key)
+	}
+	doc, err := readConfigFile()
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", ConfigFile(), err)
+	}
+	value, ok := doc.values[key]
+	return value.value, ok, nil
+}
+
+// SetConfigValue validates value and writes it to the config file
+// An existing line is replaced in place, keeping its comment, so the layout survives
+func SetConfigValue(key, value string) error {
+	s := findSetting(key)
+	if s == nil {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	if s.validate != nil {
+		if err := s.validate(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	doc, err := readConfigFile()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ConfigFile(), err)
+	}
+
+	section, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		section, name = key[:i], key[i+1:]
+	}
+	line := fmt.Sprintf("%s = %s", name, quote(value))
+
+	switch existing, ok := doc.values[key]; {
+	case ok:
+		if existing.comment != "" {
+			line += " " + existing.comment
+		}
+		doc.lines[existing.line-1] = line
+	case section == "":
+		// Top-level keys must come before the first section
+		at := doc.sections[""] + 1
+		doc.lines = append(doc.lines[:at], append([]string{line}, doc.lines[at:]...)...)
+	default:
+		last, found := doc.sections[section]
+		if !found {
+			if len(doc.lines) > 0 {
+				doc.lines = append(doc.lines, "")
+			}
+			doc.lines = append(doc.lines, "["+section+"]", line)
+			break
+		}
+		at := last + 1
+		doc.lines = append(doc.lines[:at], append([]string{line}, doc.lines[at:]...)...)
+	}
+
+	// Don't write a file that LoadConfig would then reject, e.g. two actions on one key
+	data := strings.Join(doc.lines, "\n") + "\n"
+	updated, err := parseConfig(data)
+	if err != nil {
+		return err
+	}
+	if err := validateConfig(updated); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ConfigDir(), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(ConfigFile(), []byte(data), 0o600)
+}
+
+// ConfigSetting is a key the config file accepts, with its current value
+type ConfigSetting struct {
+	Key   string
+	Help  string
+	Value string
+	Set   bool // Whether the file sets it
+}
+
+// ListConfig returns every setting with its value from the config file
+func ListConfig() ([]ConfigSetting, error) {
+	doc, err := readConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile(), err)
+	}
+
+	list := make([]ConfigSetting, len(settings))
+	for i, s := range settings {
+		value, ok := doc.values[s.key]
+		list[i] = ConfigSetting{Key: s.key, Help: s.help, Value: value.value, Set: ok}
+	}
+	return list, nil
+}
+
+// validateConfig checks every value in the file and how the keys fit together
+// Errors name the key and line at fault
+func validateConfig(doc *configDoc) error {
+	for _, s := range settings {
+		value, ok := doc.values[s.key]
+		if !ok || s.validate == nil {
+			continue
+		}
+		if err := s.validate(value.value); err != nil {
+			return fmt.Errorf("%s (line %d): %w", s.key, value.line, err)
+		}
+	}
+
+	// A working day needs both ends, in the right order
+	start, hasStart := doc.values["hours.start"]
+	end, hasEnd := doc.values["hours.end"]
+	if hasEnd && !hasStart {
+		return fmt.Errorf("hours.end (line %d): hours.start must be set too", end.line)
+	}
+	if hasStart && hasEnd {
+		if _, _, err := timeparse.ParseRange(start.value+" - "+end.value, time.Now()); err != nil {
+			return fmt.Errorf("hours.end (line %d): %w", end.line, err)
+		}
+	}
+
+	// Two actions can't share a key
+	bound := map[string]string{}
+	for action, key := range configKeys(doc) {
+		if other, taken := bound[key]; taken {
+			first, second := other, action
+			if first > second {
+				first, second = second, first
+			}
+			line := doc.values["keys."+second].line
+			if line == 0 {
+				line = doc.values["keys."+first].line
+			}
+			return fmt.Errorf("keys.%s (line %d): %q is already used by keys.%s", second, line, key, first)
+		}
+		bound[key] = action
+	}
+
+	return nil
+}
+
+// configKeys returns the key for every rebindable action, with defaults filled in
+func configKeys(doc *configDoc) map[string]string {
+	keys := make(map[string]string, len(DefaultKeys))
+	for action, key := range DefaultKeys {
+		keys[action] = key
+		if value, ok := doc.values["keys."+action]; ok {
+			keys[action] = value.value
+		}
+	}
+	return keys
+}
+
+// findSetting looks up a key the config file accepts
+func findSetting(key string) *setting {
+	for i := range settings {
+		if settings[i].key == key {
+			return &settings[i]
+		}
+	}
+	return nil
+}
+
+// validateTimeRange accepts anything the time input does
+func validateTimeRange(value string) error {
+	now := time.Now()
+	_, _, err := timeparse.ParseInput(value, now, now)
+	return err
+}
+
+// validateTimezone accepts IANA names like "America/New_York", and "Local"
+func validateTimezone(value string) error {
+	if _, err := time.LoadLocation(value); err != nil {
+		return fmt.Errorf("unknown time zone %q", value)
+	}
+	return nil
+}
+
+// validateTheme accepts the themes the UI knows
+func validateTheme(value string) error {
+	switch value {
+	case "dark", "light", "none":
+		return nil
+	}
+	return fmt.Errorf("unknown theme %q (expected dark, light or none)", value)
+}
+
+// validateTTL accepts a duration like "24h", or "0"
+func validateTTL(value string) error {
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return fmt.Errorf("%q is not a duration like 24h or 30m", value)
+	}
+	return nil
+}
+
+// validateStore accepts the credential store backends
+func validateStore(value string) error {
+	switch value {
+	case StoreAuto, StoreKeyring, StoreFile, StoreEnv:
+		return nil
+	}
+	return fmt.Errorf("unknown credential store %q (expected auto, keyring, file or env)", value)
+}
+
+// validateClock accepts a time of day like "9a" or "17:30"
+func validateClock(value string) error {
+	_, err := timeparse.ParseTime(value, time.Now())
+	return err
+}
+
+// validateKey accepts a single printable character or ctrl+<letter>
+func validateKey(value string) error {
+	if reservedKeys[value] {
+		return fmt.Errorf("%q is reserved for navigation", value)
+	}
+	if letter, ok := strings.CutPrefix(value, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size > 0 && size == len(value) && unicode.IsPrint(r) && !unicode.IsSpace(r) {
+		return nil
+	}
+	return fmt.Errorf("%q is not a single key (expected a character or ctrl+<letter>)", value)
+}
+
+// quote writes value as a basic TOML string
+func quote(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// isComment reports whether s is empty apart from whitespace and a # comment
+func isComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+// isBareKey reports whether s is a valid unquoted TOML key
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isInteger reports whether s is a whole number, optionally signed
+func isInteger(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
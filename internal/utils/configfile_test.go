@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"comments and blank lines", "# Settings\n\n  # indented\n", map[string]string{}},
+		{
+			"top-level and sections",
+			"workspace = \"Acme\"\ntheme = 'light'\n\n[hours]\nstart = \"9a\"\nend = \"5p\"\n\n[keys]\nquit = \"x\"\n",
+			map[string]string{"workspace": "Acme", "theme": "light", "hours.start": "9a", "hours.end": "5p", "keys.quit": "x"},
+		},
+		{"trailing comment", "project = \"Website\" # the usual one\n", map[string]string{"project": "Website"}},
+		{"bare value with a comment", "cache_ttl = 0 # always fetch\n", map[string]string{"cache_ttl": "0"}},
+		{"hash inside a string", "project = \"Issue #12\"\n", map[string]string{"project": "Issue #12"}},
+		{"escapes", `project = "say \"hi\" \\ there"` + "\n", map[string]string{"project": `say "hi" \ there`}},
+		{"literal strings keep backslashes", `project = 'C:\work'` + "\n", map[string]string{"project": `C:\work`}},
+		{"bare number", "cache_ttl = 0\n", map[string]string{"cache_ttl": "0"}},
+		{"section with a comment", "[hours] # working day\nstart = \"8a\"\n", map[string]string{"hours.start": "8a"}},
+		{"no trailing newline", "theme = \"none\"", map[string]string{"theme": "none"}},
+	}
+	for _, tt := range tests {
+		doc, err := parseConfig(tt.data)
+		if err != nil {
+			t.Errorf("%s: parseConfig failed: %v", tt.name, err)
+			continue
+		}
+		if len(doc.values) != len(tt.want) {
+			t.Errorf("%s: got %d values, want %d", tt.name, len(doc.values), len(tt.want))
+		}
+		for key, want := range tt.want {
+			if got := doc.values[key].value; got != want {
+				t.Errorf("%s: %s = %q, want %q", tt.name, key, got, want)
+			}
+		}
+	}
+}
+
+func TestParseConfigRejects(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string // Part of the error message
+	}{
+		{"workspace\n", "line 1: expected key = value"},
+		{"colour = \"red\"\n", `line 1: unknown key "colour"`},
+		{"[hours]\nlunch = \"12p\"\n", `line 2: unknown key "hours.lunch"`},
+		{"theme = \"dark\"\ntheme = \"light\"\n", "line 2: theme is set twice"},
+		{"theme = dark\n", "strings must be quoted"},
+		{"theme = \"dark\n", "unterminated string"},
+		{"theme = 'dark\n", "unterminated string"},
+		{"theme = \"dark\" light\n", "unexpected text after the closing quote"},
+		{`theme = "\d"` + "\n", `unsupported escape \d`},
+		{"theme =\n", "missing value"},
+		{"[hours\n", "malformed section header"},
+		{"[work hours]\n", "invalid section name"},
+		{"my key = \"x\"\n", "expected key = value"},
+	}
+	for _, tt := range tests {
+		_, err := parseConfig(tt.data)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("parseConfig(%q) error = %v, want %q", tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string // Part of the error message; empty when the file is valid
+	}{
+		{"theme = \"dark\"\ntimezone = \"Europe/Berlin\"\ncache_ttl = \"30m\"\n", ""},
+		{"[hours]\nstart = \"9a\"\nend = \"5p\"\n", ""},
+		{"[keys]\nquit = \"x\"\nsearch = \"ctrl+f\"\n", ""},
+		{"theme = \"blue\"\n", "theme (line 1): unknown theme"},
+		{"timezone = \"Mars/Olympus\"\n", "unknown time zone"},
+		{"time_range = \"soon\"\n", "time_range (line 1)"},
+		{"cache_ttl = \"-1h\"\n", "cache_ttl (line 1)"},
+		{"credential_store = \"vault\"\n", "unknown credential store"},
+		{"[hours]\nstart = \"25\"\n", "hours.start (line 2)"},
+		{"[hours]\nend = \"5p\"\n", "hours.start must be set too"},
+		{"[hours]\nstart = \"5p\"\nend = \"9a\"\n", "hours.end (line 3)"},
+		{"[keys]\nquit = \"j\"\n", "reserved for navigation"},
+		{"[keys]\nquit = \"qq\"\n", "not a single key"},
+		{"[keys]\nquit = \"w\"\n", `keys.week (line 2): "w" is already used by keys.quit`},
+	}
+	for _, tt := range tests {
+		doc, err := parseConfig(tt.data)
+		if err != nil {
+			t.Errorf("parseConfig(%q) failed: %v", tt.data, err)
+			continue
+		}
+		err = validateConfig(doc)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("validateConfig(%q) failed: %v", tt.data, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("validateConfig(%q) error = %v, want %q", tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestSetConfigValue(t *testing.T) {
+	original := "# My settings\ntheme = \"dark\" # easier on the eyes\n\n[hours]\n# When I start\nstart = \"9a\"\n\n[keys]\nquit = \"x\"\n"
+	tests := []struct {
+		key, value string
+		want       string // The whole file afterwards
+	}{
+		{
+			"theme", "light",
+			"# My settings\ntheme = \"light\" # easier on the eyes\n\n[hours]\n# When I start\nstart = \"9a\"\n\n[keys]\nquit = \"x\"\n",
+		},
+		{
+			"project", `Issue "12"`,
+			"# My settings\ntheme = \"dark\" # easier on the eyes\nproject = \"Issue \\\"12\\\"\"\n\n[hours]\n# When I start\nstart = \"9a\"\n\n[keys]\nquit = \"x\"\n",
+		},
+		{
+			"hours.end", "5p",
+			"# My settings\ntheme = \"dark\" # easier on the eyes\n\n[hours]\n# When I start\nstart = \"9a\"\nend = \"5p\"\n\n[keys]\nquit = \"x\"\n",
+		},
+		{
+			"keys.search", "ctrl+f",
+			"# My settings\ntheme = \"dark\" # easier on the eyes\n\n[hours]\n# When I start\nstart = \"9a\"\n\n[keys]\nquit = \"x\"\nsearch = \"ctrl+f\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Setenv("HOME", t.TempDir())
+		writeConfig(t, original)
+
+		if err := SetConfigValue(tt.key, tt.value); err != nil {
+			t.Errorf("SetConfigValue(%q, %q) failed: %v", tt.key, tt.value, err)
+			continue
+		}
+		if got := readConfig(t); got != tt.want {
+			t.Errorf("SetConfigValue(%q, %q) wrote\n%s\nwant\n%s", tt.key, tt.value, got, tt.want)
+		}
+
+		// What was written reads back as the same value
+		value, ok, err := GetConfigValue(tt.key)
+		if err != nil || !ok || value != tt.value {
+			t.Errorf("GetConfigValue(%q) = %q, %v, %v; want %q", tt.key, value, ok, err, tt.value)
+		}
+	}
+}
+
+func TestSetConfigValueNewFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, set := range [][2]string{{"keys.quit", "x"}, {"workspace", "Acme"}, {"hours.start", "8a"}} {
+		if err := SetConfigValue(set[0], set[1]); err != nil {
+			t.Fatalf("SetConfigValue(%q, %q) failed: %v", set[0], set[1], err)
+		}
+	}
+
+	// Top-level keys go before the first section, new sections at the end
+	want := "workspace = \"Acme\"\n[keys]\nquit = \"x\"\n\n[hours]\nstart = \"8a\"\n"
+	if got := readConfig(t); got != want {
+		t.Errorf("config file is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSetConfigValueRejects(t *testing.T) {
+	original := "[keys]\nquit = \"x\"\n"
+	tests := []struct {
+		key, value string
+		err        string // Part of the error message
+	}{
+		{"colour", "red", `unknown key "colour"`},
+		{"theme", "blue", "theme: unknown theme"},
+		{"hours.end", "5p", "hours.start must be set too"},
+		{"keys.week", "x", `"x" is already used by keys.quit`},
+	}
+	for _, tt := range tests {
+		t.Setenv("HOME", t.TempDir())
+		writeConfig(t, original)
+
+		err := SetConfigValue(tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("SetConfigValue(%q, %q) error = %v, want %q", tt.key, tt.value, err, tt.err)
+		}
+		if got := readConfig(t); got != original {
+			t.Errorf("SetConfigValue(%q, %q) changed the file to\n%s", tt.key, tt.value, got)
+		}
+	}
+}
+
+// writeConfig replaces the config file under the current HOME
+func writeConfig(t *testing.T, data string) {
+	t.Helper()
+	if err := os.MkdirAll(ConfigDir(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ConfigFile(), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readConfig returns the config file under the current HOME
+func readConfig(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(ConfigFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,410 @@
+// Keeps API keys out of plaintext files
+// A CredentialStore saves one key per account: "default" for the plain .env
+// settings, or a profile's name. The backend is picked with credential_store in config.toml
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/joho/godotenv"
+)
+
+// DefaultAccount is the account used without a profile
+const DefaultAccount = "default"
+
+// Credential store backends, as written in config.toml
+const (
+	StoreAuto    = "auto"    // Secret Service if secret-tool is installed, otherwise the env file
+	StoreKeyring = "keyring" // freedesktop Secret Service (GNOME Keyring, KWallet) via secret-tool
+	StoreFile    = "file"    // credentials.enc, encrypted with a passphrase
+	StoreEnv     = "env"     // CLOCKIFY_API_KEY in the .env file, as before
+)
+
+// CredentialStore saves and looks up API keys
+type CredentialStore interface {
+	Name() string                       // Shown to the user, e.g. "Secret Service"
+	Get(account string) (string, error) // "" without an error when nothing is stored
+	Set(account, apiKey string) error
+}
+
+// OpenCredentialStore returns the backend for kind ("" means auto)
+func OpenCredentialStore(kind string) (CredentialStore, error) {
+	switch kind {
+	case "", StoreAuto:
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return secretServiceStore{}, nil
+		}
+		return envFileStore{}, nil
+	case StoreKeyring:
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, fmt.Errorf("the keyring store needs secret-tool (install libsecret-tools or libsecret)")
+		}
+		return secretServiceStore{}, nil
+	case StoreFile:
+		return encryptedFileStore{path: filepath.Join(ConfigDir(), "credentials.enc")}, nil
+	case StoreEnv:
+		return envFileStore{}, nil
+	}
+	return nil, fmt.Errorf("unknown credential store %q (expected auto, keyring, file or env)", kind)
+}
+
+// UnlockCredentials asks for the encrypted credential file's passphrase now, if
+// that's the configured store, so the profile picker can load keys later while
+// the UI owns the terminal. Other stores have nothing to unlock
+func UnlockCredentials() error {
+	kind, err := StoreKind()
+	if err != nil || kind != StoreFile {
+		return err
+	}
+	store, err := OpenCredentialStore(kind)
+	if err != nil {
+		return err
+	}
+	_, err = store.Get(DefaultAccount)
+	return err
+}
+
+// StoreKind returns the configured credential_store ("auto" when unset)
+func StoreKind() (string, error) {
+	value, ok, err := GetConfigValue("credential_store")
+	if err != nil || !ok {
+		return StoreAuto, err
+	}
+	return value, nil
+}
+
+// account returns the credential account for a profile
+func account(profile string) string {
+	if profile == "" {
+		return DefaultAccount
+	}
+	return profile
+}
+
+// envFile is the env file holding an account's settings
+func envFile(account string) string {
+	if account == DefaultAccount {
+		return filepath.Join(ConfigDir(), ".env")
+	}
+	return filepath.Join(ProfilesDir(), account+".env")
+}
+
+// secretServiceStore keeps keys in the desktop keyring through the secret-tool CLI,
+// so there's no D-Bus code to maintain here
+type secretServiceStore struct{}
+
+func (secretServiceStore) Name() string { return "Secret Service" }
+
+func (secretServiceStore) Get(account string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", "clockify-tracker", "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits 1 without output when there's simply no such secret
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return "", nil
+		}
+		return "", fmt.Errorf("keyring lookup failed: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (secretServiceStore) Set(account, apiKey string) error {
+	// The secret goes in on stdin so it never shows up in the process list
+	cmd := exec.Command("secret-tool", "store", "--label", "Clockify API key ("+account+")",
+		"service", "clockify-tracker", "account", account)
+	cmd.Stdin = strings.NewReader(apiKey)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("saving to the keyring failed: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// envFileStore is the original plaintext CLOCKIFY_API_KEY in .env
+type envFileStore struct{}
+
+func (envFileStore) Name() string { return "env file" }
+
+func (envFileStore) Get(account string) (string, error) {
+	values, err := godotenv.Read(envFile(account))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return values["CLOCKIFY_API_KEY"], nil
+}
+
+func (envFileStore) Set(account, apiKey string) error {
+	return setEnvValue(envFile(account), "CLOCKIFY_API_KEY", apiKey)
+}
+
+// SaveAPIKey saves a key for a profile ("" for the default account) in the
+// store for kind, and removes any plaintext copy the store makes redundant
+// Reports whether a plaintext copy was removed
+func SaveAPIKey(profile, kind, apiKey string) (CredentialStore, bool, error) {
+	store, err := OpenCredentialStore(kind)
+	if err != nil {
+		return nil, false, err
+	}
+	if profile != "" {
+		if err := CreateProfile(profile); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := store.Set(account(profile), apiKey); err != nil {
+		return nil, false, err
+	}
+
+	// The old plaintext copy would otherwise still win over the stored key
+	if IsPlaintextStore(store) {
+		return store, false, nil
+	}
+	removed, err := RemovePlaintextKey(profile)
+	if err != nil {
+		return store, false, fmt.Errorf("the key is saved, but removing the plaintext copy failed: %w", err)
+	}
+	return store, removed, nil
+}
+
+// IsPlaintextStore reports whether store is the plain .env backend
+func IsPlaintextStore(store CredentialStore) bool {
+	_, ok := store.(envFileStore)
+	return ok
+}
+
+// RemovePlaintextKey deletes CLOCKIFY_API_KEY from an account's env file once
+// the key lives in a safer store. Reports whether there was one to remove
+func RemovePlaintextKey(profile string) (bool, error) {
+	key, err := envFileStore{}.Get(account(profile))
+	if err != nil || key == "" {
+		return false, err
+	}
+	return true, setEnvValue(envFile(account(profile)), "CLOCKIFY_API_KEY", "")
+}
+
+// setEnvValue sets name=value in an env file, or removes it when value is ""
+// Other lines, comments included, are kept as they are
+func setEnvValue(path, name, value string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var lines []string
+	found := false
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		if strings.HasPrefix(trimmed, name+"=") {
+			if value != "" && !found {
+				lines = append(lines, name+"="+value)
+			}
+			found = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if !found && value != "" {
+		lines = append(lines, name+"="+value)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.TrimLeft(strings.Join(lines, "\n"), "\n")+"\n"), 0o600)
+}
+
+// encryptedFileStore keeps every account's key in one AES-GCM encrypted file
+// The key is derived from a passphrase, taken from CLOCKIFY_PASSPHRASE or asked for
+type encryptedFileStore struct {
+	path string
+}
+
+// encryptedFile is the JSON layout of credentials.enc ([]byte fields are base64)
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"` // Encrypted JSON object of account -> API key
+}
+
+// pbkdf2Iterations follows OWASP's 2023 advice for PBKDF2-HMAC-SHA256
+const pbkdf2Iterations = 600_000
+
+// passphrase is remembered for the rest of the run, so picking a profile
+// in the UI doesn't ask again
+var passphrase string
+
+func (s encryptedFileStore) Name() string { return "encrypted file " + s.path }
+
+func (s encryptedFileStore) Get(account string) (string, error) {
+	keys, err := s.read(false)
+	if err != nil {
+		return "", err
+	}
+	return keys[account], nil
+}
+
+func (s encryptedFileStore) Set(account, apiKey string) error {
+	keys, err := s.read(true)
+	if err != nil {
+		return err
+	}
+	keys[account] = apiKey
+	return s.write(keys)
+}
+
+// read decrypts the file; a missing file is empty, and only asks for a
+// new passphrase when creating is true
+func (s encryptedFileStore) read(creating bool) (map[string]string, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if creating {
+			if err := askPassphrase(true); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("%s is damaged: %w", s.path, err)
+	}
+	if err := askPassphrase(false); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		passphrase = "" // Let the next attempt ask again
+		return nil, fmt.Errorf("wrong passphrase for %s", s.path)
+	}
+
+	keys := map[string]string{}
+	if err := json.Unmarshal(plain, &keys); err != nil {
+		return nil, fmt.Errorf("%s is damaged: %w", s.path, err)
+	}
+	return keys, nil
+}
+
+// write encrypts keys with a fresh salt and nonce, replacing the file atomically
+func (s encryptedFileStore) write(keys map[string]string) error {
+	plain, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	file := encryptedFile{Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(s.path, content, 0o600)
+}
+
+// newGCM derives the AES-256 key from the passphrase and salt
+func newGCM(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// askPassphrase fills in passphrase from CLOCKIFY_PASSPHRASE or the terminal
+// A new passphrase is asked for twice so a typo doesn't lock the user out
+func askPassphrase(confirm bool) error {
+	if passphrase != "" {
+		return nil
+	}
+	if value := os.Getenv("CLOCKIFY_PASSPHRASE"); value != "" {
+		passphrase = value
+		return nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return fmt.Errorf("the encrypted credential file needs a passphrase: set CLOCKIFY_PASSPHRASE or run in a terminal")
+	}
+
+	first, err := readSecret("Passphrase for Clockify credentials: ")
+	if err != nil {
+		return err
+	}
+	if first == "" {
+		return fmt.Errorf("passphrase can't be empty")
+	}
+	if confirm {
+		second, err := readSecret("Repeat passphrase: ")
+		if err != nil {
+			return err
+		}
+		if first != second {
+			return fmt.Errorf("passphrases don't match")
+		}
+	}
+	passphrase = first
+	return nil
+}
+
+// ReadSecret asks for a secret on the terminal without echoing it
+// When stdin isn't a terminal (e.g., piped in a script), the first line is read instead
+func ReadSecret(prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("no input on stdin")
+		}
+		return strings.TrimSpace(line), nil
+	}
+	return readSecret(prompt)
+}
+
+// readSecret prompts on stderr so stdout stays clean for scripts
+func readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	value, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(value)), nil
+}
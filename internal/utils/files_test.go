@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "nested", "data.json")
+
+	// Missing directories are created, and a second write replaces the first
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFileAtomic(file, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic failed: %v", err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil || string(data) != content {
+			t.Errorf("file holds %q, %v; want %q", data, err, content)
+		}
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %v, want 0600", perm)
+	}
+
+	// No temporary files are left next to it
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only data.json", len(entries))
+	}
+}
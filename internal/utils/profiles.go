@@ -0,0 +1,112 @@
+// Named profiles for people with more than one Clockify account
+// Each profile is an env file in ~/.config/clockify-tracker/profiles/, e.g. work.env,
+// with its own CLOCKIFY_API_KEY (unless it's in the credential store) and optionally
+// CLOCKIFY_WORKSPACE and CLOCKIFY_BASE_URL
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Profile is one Clockify account's connection settings
+type Profile struct {
+	Name      string
+	APIKey    string // "" when the key is in the credential store
+	Workspace string // Workspace ID or name ("" to ask)
+	BaseURL   string // API base URL ("" for Clockify's default)
+}
+
+// ProfilesDir is where profile env files live
+func ProfilesDir() string {
+	return filepath.Join(ConfigDir(), "profiles")
+}
+
+// ListProfiles returns the names of all profiles, sorted
+func ListProfiles() ([]string, error) {
+	files, err := os.ReadDir(ProfilesDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var names []string
+	for _, file := range files {
+		if name, ok := strings.CutSuffix(file.Name(), ".env"); ok && !file.IsDir() && validProfileName(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadProfile reads a profile's env file
+// Unlike the main .env, nothing is copied into the environment, so switching
+// profiles can't leave another account's settings behind
+func LoadProfile(name string) (*Profile, error) {
+	if !validProfileName(name) {
+		return nil, fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", name)
+	}
+
+	path := filepath.Join(ProfilesDir(), name+".env")
+	values, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("profile %q not found (expected %s)", name, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", name, err)
+	}
+
+	return &Profile{
+		Name:      name,
+		APIKey:    values["CLOCKIFY_API_KEY"],
+		Workspace: values["CLOCKIFY_WORKSPACE"],
+		BaseURL:   values["CLOCKIFY_BASE_URL"],
+	}, nil
+}
+
+// HasProfile reports whether a profile's env file exists
+func HasProfile(name string) bool {
+	if !validProfileName(name) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(ProfilesDir(), name+".env"))
+	return err == nil
+}
+
+// CreateProfile adds an empty env file for a profile whose key lives in the
+// credential store, so it still shows up in the profile list
+func CreateProfile(name string) error {
+	if !validProfileName(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", name)
+	}
+	if HasProfile(name) {
+		return nil
+	}
+	if err := os.MkdirAll(ProfilesDir(), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ProfilesDir(), name+".env"), []byte("# Clockify profile - the API key is in the credential store\n"), 0o600)
+}
+
+// validProfileName keeps profile names safe to use as file names
+func validProfileName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
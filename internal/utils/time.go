@@ -1,32 +1,18 @@
+// Date helpers shared across the application
 package utils
 
 import (
-	"fmt"
-	"strings"
 	"time"
 )
 
-func parseTime(timeStr string, date time.Time) time.Time {
-	timeStr = strings.ToLower(strings.TrimSpace(timeStr))
-	timeStr = strings.ReplaceAll(timeStr, " ", "")
-
-	var hour, minute int
-	isPM := strings.HasSuffix(timeStr, "p") || strings.HasSuffix(timeStr, "pm")
-	timeStr = strings.TrimSuffix(strings.TrimSuffix(timeStr, "p"), "m")
-	timeStr = strings.TrimSuffix(strings.TrimSuffix(timeStr, "a"), "m")
-
-	if strings.Contains(timeStr, ":") {
-		fmt.Sscanf(timeStr, "%d:%d", &hour, &minute)
-	} else {
-		fmt.Sscanf(timeStr, "%d", &hour)
-	}
-
-	if isPM && hour != 12 {
-		hour += 12
-	} else if !isPM && hour == 12 {
-		hour = 0
-	}
-
-	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+// StartOfDay returns midnight at the beginning of t's day, in t's location
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
 
+// StartOfWeek returns midnight on the Monday of t's week
+// Go's time.Weekday starts on Sunday, so shift it to make Monday day 0
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
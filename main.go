@@ -2,31 +2,156 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	
+
+	"clockify-time-tracker/internal/api"
+	"clockify-time-tracker/internal/cli"
 	"clockify-time-tracker/internal/ui"
 	"clockify-time-tracker/internal/utils"
 )
 
 func main() {
+	// --profile comes before any subcommand, e.g. `clockify-tracker --profile client log ...`
+	profile, args, err := profileFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// `config` edits the settings themselves, so it must work even when they're broken
+	if len(args) > 0 && args[0] == "config" {
+		if err := cli.RunConfig(args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// `login` saves the API key, so it can't need one to start
+	if len(args) > 0 && args[0] == "login" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := cli.RunLogin(ctx, profile, args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration from .env file
-	// This will read CLOCKIFY_API_KEY and return an error if not found
-	config, err := utils.LoadConfig()
+	// This will read CLOCKIFY_API_KEY (or the profile's key) and return an error if not found
+	config, err := utils.LoadConfig(profile)
+	if errors.Is(err, utils.ErrNoAPIKey) && len(args) == 0 {
+		// First run of the interactive app - walk the user through setup instead
+		config, err = firstRun(profile)
+	}
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Subcommands run without the TUI so they can be scripted
+	// ctrl+c cancels their context so in-flight requests stop right away
+	if len(args) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		switch args[0] {
+		case "log":
+			err := cli.RunLog(ctx, config, args[1:], os.Stdout)
+			if errors.Is(err, cli.ErrQueued) {
+				os.Exit(3) // Not lost, but not in Clockify yet either
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "sync":
+			if err := cli.RunSync(ctx, config, args[1:], os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown command %q\n", args[0])
+			os.Exit(2)
+		}
+	}
+
+	// The profile picker can't ask for a passphrase once the UI is running
+	if config.Profile == "" && len(config.Profiles) > 0 {
+		if err := utils.UnlockCredentials(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Create a new Bubble Tea program with our UI model
 	// The ui.New() function initializes the model with our config
 	p := tea.NewProgram(ui.New(config))
-	
+
 	// Run the program - this starts the interactive TUI
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// profileFlag pulls a leading --profile name (or --profile=name) off the arguments
+// Returns the profile ("" if none was given) and the arguments left over
+func profileFlag(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", args, nil
+	}
+	if name, ok := strings.CutPrefix(args[0], "--profile="); ok {
+		return name, args[1:], nil
+	}
+	if args[0] == "--profile" {
+		if len(args) < 2 {
+			return "", nil, fmt.Errorf("--profile needs a profile name")
+		}
+		return args[1], args[2:], nil
+	}
+	return "", args, nil
+}
+
+// firstRun runs the setup wizard, saves the API key and chosen defaults,
+// then loads the config again as if it had been there all along
+func firstRun(profile string) (*utils.Config, error) {
+	setup, err := ui.RunSetup(api.WithBaseURL(os.Getenv("CLOCKIFY_BASE_URL")))
+	if err != nil {
+		return nil, err
+	}
+	if setup == nil {
+		return nil, fmt.Errorf("setup cancelled - run again, or use `clockify-tracker login`")
+	}
+
+	// Saved outside the UI, so an encrypted store can ask for its passphrase
+	kind, err := utils.StoreKind()
+	if err != nil {
+		return nil, err
+	}
+	store, _, err := utils.SaveAPIKey(profile, kind, setup.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.SetConfigValue("workspace", setup.Workspace.Name); err != nil {
+		return nil, err
+	}
+	if setup.Project.ID != "" {
+		if err := utils.SetConfigValue("project", setup.Project.Name); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Printf("API key saved to the %s; defaults saved to %s\n", store.Name(), utils.ConfigFile())
+
+	return utils.LoadConfig(profile)
+}